@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/j03hanafi/seternak-backend/config"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/handler/request"
+	"github.com/j03hanafi/seternak-backend/repository"
+	"github.com/j03hanafi/seternak-backend/service"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+// createAdminCmd signs up a user and grants them administrator rights
+var createAdminCmd = &cobra.Command{
+	Use:   "create-admin",
+	Short: "Create an administrator account",
+	Long: "Create an administrator account through the regular sign up flow.\n" +
+		"The password is read from standard input when --password is not given.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		email, _ := cmd.Flags().GetString("email")
+		name, _ := cmd.Flags().GetString("name")
+		password, _ := cmd.Flags().GetString("password")
+
+		if password == "" {
+			cmd.Print("Password: ")
+			line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("error reading password: %w", err)
+			}
+			password = strings.TrimRight(line, "\r\n")
+		}
+
+		// validate the same way the sign up endpoint does
+		req := request.SignUp{Email: email, Password: password, Name: name}
+		if err := req.Validate(); err != nil {
+			return err
+		}
+
+		cfg := config.NewDB()
+		defer func() {
+			_ = cfg.Close()
+		}()
+
+		userService := service.NewUser(&service.UserServiceConfig{
+			UserRepository: repository.NewPGUser(cfg.GetDB()),
+			Transactor:     repository.NewPGTransactor(cfg.GetDB()),
+		})
+
+		ctx := context.Background()
+		user := &domain.User{
+			Email:    req.Email,
+			Password: req.Password,
+			Name:     req.Name,
+		}
+
+		if err := userService.CreateAdmin(ctx, user); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created admin %s (%s)\n", user.Email, user.UID)
+		return nil
+	},
+}
+
+func init() {
+	createAdminCmd.Flags().String("email", "", "email address of the administrator (required)")
+	createAdminCmd.Flags().String("name", "Administrator", "display name of the administrator")
+	createAdminCmd.Flags().String("password", "", "password of the administrator, prompted when empty")
+	_ = createAdminCmd.MarkFlagRequired("email")
+
+	rootCmd.AddCommand(createAdminCmd)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/j03hanafi/seternak-backend/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"sort"
+	"strings"
+)
+
+// redacted replaces secret values in printed configuration
+const redacted = "[REDACTED]"
+
+// secretKeySuffixes mark configuration keys whose values must never be printed
+var secretKeySuffixes = []string{"PASS", "PASSWORD", "SECRET", "TOKEN", "KEY", "DSNS"}
+
+// configCmd groups the configuration subcommands
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the effective configuration with secrets redacted",
+	Long: "Print the effective configuration, merged from defaults, the .env file\n" +
+		"and environment variables, in .env format with secrets redacted.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		config.Load()
+
+		keys := viper.AllKeys()
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			name := strings.ToUpper(key)
+
+			value := viper.GetString(key)
+			if value != "" && isSecretKey(name) {
+				value = redacted
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s=%s\n", name, value)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configPrintCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// isSecretKey reports whether the configuration key holds a secret
+func isSecretKey(name string) bool {
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
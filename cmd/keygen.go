@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/j03hanafi/seternak-backend/config"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+// errAlreadyExists is returned when refusing to overwrite existing key files
+var errAlreadyExists = errors.New("already exists, pass --force to overwrite")
+
+// keygenCmd generates the RSA key pair used to sign and verify ID tokens
+var keygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate the RSA key pair used to sign ID tokens",
+	Long: "Generate the RSA key pair used to sign ID tokens.\n" +
+		"Keys are written to PRIVATE_KEY_FILE and PUBLIC_KEY_FILE unless overridden by flags.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		// Not validated, as the key files are not expected to exist yet
+		settings, err := config.LoadSettings()
+		if err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		var (
+			privateFile = settings.Auth.PrivateKeyFile
+			publicFile  = settings.Auth.PublicKeyFile
+		)
+		if f, _ := cmd.Flags().GetString("private"); f != "" {
+			privateFile = f
+		}
+		if f, _ := cmd.Flags().GetString("public"); f != "" {
+			publicFile = f
+		}
+		bits, _ := cmd.Flags().GetInt("bits")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if !force {
+			for _, f := range []string{privateFile, publicFile} {
+				if _, err := os.Stat(f); err == nil {
+					return fmt.Errorf("%s %w", f, errAlreadyExists)
+				}
+			}
+		}
+
+		privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return fmt.Errorf("error generating key: %w", err)
+		}
+
+		privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return fmt.Errorf("error encoding private key: %w", err)
+		}
+
+		publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		if err != nil {
+			return fmt.Errorf("error encoding public key: %w", err)
+		}
+
+		if err = writePEM(privateFile, "PRIVATE KEY", privateDER, 0o600); err != nil {
+			return err
+		}
+
+		if err = writePEM(publicFile, "PUBLIC KEY", publicDER, 0o644); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d-bit RSA key pair to %s and %s\n", bits, privateFile, publicFile)
+		return nil
+	},
+}
+
+func init() {
+	keygenCmd.Flags().String("private", "", "private key output file (default PRIVATE_KEY_FILE)")
+	keygenCmd.Flags().String("public", "", "public key output file (default PUBLIC_KEY_FILE)")
+	keygenCmd.Flags().Int("bits", 2048, "RSA key size in bits")
+	keygenCmd.Flags().Bool("force", false, "overwrite existing key files")
+
+	rootCmd.AddCommand(keygenCmd)
+}
+
+// writePEM writes a single PEM block to file, truncating any existing content
+func writePEM(file, blockType string, der []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", file, err)
+	}
+
+	if err = pem.Encode(f, &pem.Block{Type: blockType, Bytes: der}); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error writing %s: %w", file, err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/j03hanafi/seternak-backend/config"
+	"github.com/j03hanafi/seternak-backend/migrations"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/spf13/cobra"
+	"strconv"
+	"text/tabwriter"
+)
+
+// migrateCmd groups the database migration subcommands
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage database migrations embedded in the binary",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up [N]",
+	Short: "Apply the next N pending migrations, or all of them",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := stepsArg(args, 0)
+		if err != nil {
+			return err
+		}
+		return migrateUp(n)
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [N]",
+	Short: "Revert the last N applied migrations, 1 by default",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := stepsArg(args, 1)
+		if err != nil {
+			return err
+		}
+
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			n = 0
+		}
+
+		return withMigrator(func(m *migrations.Migrator) error {
+			return m.Down(n)
+		})
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List embedded migrations and whether they are applied",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return withMigrator(func(m *migrations.Migrator) error {
+			statuses, err := m.Status()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+			for _, s := range statuses {
+				status := "pending"
+				if s.Dirty {
+					status = "dirty"
+				} else if s.Applied {
+					status = "applied"
+				}
+				_, _ = fmt.Fprintf(w, "%05d\t%s\t%s\n", s.Version, s.Name, status)
+			}
+			return w.Flush()
+		})
+	},
+}
+
+var migrateForceCmd = &cobra.Command{
+	Use:   "force VERSION",
+	Short: "Set the migration version without running migrations and clear the dirty flag",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[0], err)
+		}
+
+		return withMigrator(func(m *migrations.Migrator) error {
+			return m.Force(version)
+		})
+	},
+}
+
+func init() {
+	migrateDownCmd.Flags().Bool("all", false, "revert every applied migration")
+
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd, migrateForceCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
+
+// migrateUp applies n pending migrations, or all of them when n is 0
+func migrateUp(n int) error {
+	return withMigrator(func(m *migrations.Migrator) error {
+		return m.Up(n)
+	})
+}
+
+// withMigrator loads the PG settings, runs fn with a Migrator and closes it afterwards
+func withMigrator(fn func(m *migrations.Migrator) error) error {
+	settings, err := config.LoadSettings()
+	if err == nil {
+		err = settings.ValidateDB()
+	}
+	if err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	m, err := migrations.New(settings.DB.DSN(), settings.DB.MigrateLockTimeout, logger.Get())
+	if err != nil {
+		return err
+	}
+
+	if err = fn(m); err != nil {
+		_ = m.Close()
+		return err
+	}
+
+	return m.Close()
+}
+
+// stepsArg parses the optional number of migrations to run, returning def when omitted
+func stepsArg(args []string, def int) (int, error) {
+	if len(args) == 0 {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number of migrations %q", args[0])
+	}
+
+	return n, nil
+}
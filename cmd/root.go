@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"os"
+)
+
+// rootCmd is the base command, every feature of the binary is a subcommand
+var rootCmd = &cobra.Command{
+	Use:           "seternak-backend",
+	Short:         "Seternak backend API server and maintenance commands",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+// Execute runs the command selected by the command-line arguments.
+// It exits with a non-zero status if the command fails.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		rootCmd.PrintErrln("Error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/j03hanafi/seternak-backend/config"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/repository"
+	"github.com/j03hanafi/seternak-backend/service"
+	"github.com/spf13/cobra"
+)
+
+// demoUsers are the accounts created by the seed command
+var demoUsers = []domain.User{
+	{Email: "demo@seternak.id", Name: "Demo Farmer"},
+	{Email: "worker@seternak.id", Name: "Demo Worker"},
+}
+
+// seedCmd fills the database with demo data
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Fill the database with demo data",
+	Long: "Fill the database with demo accounts and their welcome notifications.\n" +
+		"Accounts that already exist are left untouched, so seeding can be repeated.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		password, _ := cmd.Flags().GetString("password")
+		force, _ := cmd.Flags().GetBool("force")
+
+		cfg := config.NewDB()
+		defer func() {
+			_ = cfg.Close()
+		}()
+
+		if cfg.GetSettings().App.Production() && !force {
+			return errors.New("refusing to seed demo accounts in production, pass --force to seed anyway")
+		}
+
+		userRepository := repository.NewPGUser(cfg.GetDB())
+		userService := service.NewUser(&service.UserServiceConfig{
+			UserRepository: userRepository,
+		})
+		notificationService := service.NewNotification(&service.NotificationServiceConfig{
+			NotificationRepository: repository.NewPGNotification(cfg.GetDB()),
+			UserRepository:         userRepository,
+		})
+
+		ctx := context.Background()
+		for _, demo := range demoUsers {
+			user := demo
+			user.Password = password
+
+			err := userService.SignUp(ctx, &user)
+			var appErr *apperrors.Error
+			if errors.As(err, &appErr) && appErr.Type == apperrors.Conflict {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Skipped %s, it already exists\n", user.Email)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			err = notificationService.Notify(ctx, &domain.Notification{
+				UserUID:  user.UID,
+				Category: domain.NotificationCategorySystem,
+				Title:    "Welcome to Seternak",
+				Body:     fmt.Sprintf("Hi %s, this is a demo account. Explore freely!", user.Name),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", user.Email)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	seedCmd.Flags().String("password", "seternak123", "password of the demo accounts")
+	seedCmd.Flags().Bool("force", false, "seed even when APP_ENV is production")
+
+	rootCmd.AddCommand(seedCmd)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/server"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// serveCmd starts the HTTP server
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Start the HTTP API server",
+	Args:  cobra.NoArgs,
+	RunE:  runServe,
+}
+
+func init() {
+	serveCmd.Flags().String("addr", ":8080", "address to listen on (env LISTEN_ADDR)")
+	serveCmd.Flags().Bool("auto-migrate", false, "apply pending database migrations before starting (env DB_AUTO_MIGRATE)")
+	_ = viper.BindPFlag("LISTEN_ADDR", serveCmd.Flags().Lookup("addr"))
+	_ = viper.BindPFlag("DB_AUTO_MIGRATE", serveCmd.Flags().Lookup("auto-migrate"))
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+// runServe starts the HTTP server and blocks until it is shut down by a signal
+func runServe(_ *cobra.Command, _ []string) error {
+	var err error
+
+	l := logger.Get()
+
+	// Initialize Fiber app, which loads and validates the settings
+	app := server.New()
+	settings := app.Settings()
+
+	// Apply migrations before serving any request
+	if settings.DB.AutoMigrate {
+		l.Info("Applying database migrations...")
+		if err = migrateUp(0); err != nil {
+			l.Fatal("Error applying database migrations", zap.Error(err))
+		}
+	}
+
+	// Create context that listens for the interrupt signal from the OS.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGKILL, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		if err = app.Listen(settings.App.ListenAddr); err != nil {
+			log.Fatal("Server Error", err)
+		}
+	}()
+	l.Info("Server is starting...")
+
+	// Graceful Shutdown
+	<-ctx.Done()
+	stop()
+	l.Info("shutting down gracefully, press Ctrl+C again to force")
+
+	// Fail readiness first, so load balancers drain traffic before the listener closes
+	app.Drain()
+	time.Sleep(settings.App.ShutdownDrainDelay)
+
+	err = app.ShutdownWithTimeout(5 * time.Second)
+	if err != nil {
+		l.Fatal("Server forced to shutdown", zap.Error(err))
+	}
+
+	if err = app.Close(); err != nil {
+		l.Fatal("Error closing Fiber app", zap.Error(err))
+	}
+	l.Info("Server was successful shutdown.")
+
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"crypto/rsa"
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/goccy/go-json"
@@ -14,8 +15,18 @@ import (
 	"github.com/j03hanafi/seternak-backend/handler/response"
 	"github.com/j03hanafi/seternak-backend/utils/consts"
 	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/j03hanafi/seternak-backend/utils/replica"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -28,6 +39,7 @@ import (
 
 // Config defines configuration settings for the server.
 type Config struct {
+	settings    *Settings
 	db          *gorm.DB
 	fiber       *fiber.Config
 	fiberzap    *fiberzap.Config
@@ -36,14 +48,62 @@ type Config struct {
 	privateKey  *rsa.PrivateKey
 	publicKey   *rsa.PublicKey
 	zapLogger   *zap.Logger
+	tracer      *sdktrace.TracerProvider
+	replicas    *replica.Router
 }
 
-// New initializes a new Config struct, sets default values, and loads environment variables.
-// It returns a pointer to the Config struct, or logs a fatal error if config loading fails.
+// New initializes a new Config struct, sets default values, and loads environment variables
+// into validated Settings. It returns a pointer to the Config struct, or logs a fatal error
+// listing every invalid setting if config loading fails.
 func New() *Config {
 	config := &Config{}
 
-	config.setDefaults()
+	settings, err := LoadSettings()
+	if err == nil {
+		err = settings.Validate()
+	}
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	config.settings = settings
+
+	// Set Config struct field values
+	config.setLogger()
+	config.setTracer()
+	config.setDB()
+	config.setFiberConfig()
+	config.setFiberzapConfig()
+	config.setRecoverConfig()
+	config.setRedis()
+	config.setRSAKeys()
+
+	return config
+}
+
+// NewDB initializes a Config with only the logger and the database connection set,
+// for commands that work on the database without serving HTTP requests.
+func NewDB() *Config {
+	config := &Config{}
+
+	settings, err := LoadSettings()
+	if err == nil {
+		err = settings.ValidateDB()
+	}
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	config.settings = settings
+
+	config.setLogger()
+	config.setDB()
+
+	return config
+}
+
+// Load sets default values and loads the .env file and environment variables into viper,
+// without connecting to any dependency. It logs a fatal error if the config file cannot be read.
+func Load() {
+	setDefaults()
 
 	viper.SetConfigName(".env")
 	viper.SetConfigType("dotenv")
@@ -59,44 +119,87 @@ func New() *Config {
 			log.Fatalf("Error reading config file, %v", err)
 		}
 	}
-
-	// Set Config struct field values
-	config.setLogger()
-	config.setDB()
-	config.setFiberConfig()
-	config.setFiberzapConfig()
-	config.setRecoverConfig()
-	config.setRedis()
-	config.setRSAKeys()
-
-	return config
 }
 
 // setDefaults sets default values for application configuration.
-func (c *Config) setDefaults() {
+func setDefaults() {
 	// Set default App configuration
 	viper.SetDefault("APP_ENV", consts.DevelopmentMode)
 	viper.SetDefault("API_URL", "/api/v1")
+	viper.SetDefault("LISTEN_ADDR", ":8080")
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")
+	viper.SetDefault("SHUTDOWN_DRAIN_DELAY", "5s") // time for load balancers to notice readiness failing
+
+	// Set default Log configuration
+	// LOG_LEVEL defaults to debug, or info in production. LOG_FILE defaults to logs/app.log
+	// in production and to no file otherwise; none turns the file sink off in production too
+	viper.SetDefault("LOG_LEVEL", "")
+	viper.SetDefault("LOG_FORMAT", logger.FormatConsole)
+	viper.SetDefault("LOG_FILE", logger.FileDefault)
+	viper.SetDefault("LOG_FILE_MAX_SIZE", 5) // megabytes
+	viper.SetDefault("LOG_FILE_MAX_AGE", 30) // days
+	viper.SetDefault("LOG_FILE_MAX_BACKUPS", 15)
+	viper.SetDefault("LOG_FILE_COMPRESS", true)
+	viper.SetDefault("LOG_SAMPLING", false)
+	viper.SetDefault("LOG_SAMPLING_INITIAL", 100)    // entries logged per second for a message
+	viper.SetDefault("LOG_SAMPLING_THEREAFTER", 100) // then every Nth entry within that second
 
 	// Set default DB configuration
 	viper.SetDefault("PG_HOST", "localhost")
 	viper.SetDefault("PG_PORT", "5432")
 	viper.SetDefault("PG_USER", "postgres")
-	viper.SetDefault("PG_PASS", "password")
+	viper.SetDefault("PG_PASS", defaultPGPass)
 	viper.SetDefault("PG_DB", "seternak")
 	viper.SetDefault("PG_SSL", "disable")
+	viper.SetDefault("DB_AUTO_MIGRATE", false)
+	viper.SetDefault("DB_MIGRATE_LOCK_TIMEOUT", "1m")
+	// PG_REPLICA_DSNS is a comma separated list of read replica connection strings;
+	// reads go to the primary when it is empty or every replica is down
+	viper.SetDefault("PG_REPLICA_DSNS", []string{})
+	viper.SetDefault("DB_READ_YOUR_WRITES", true) // reads after a write in a request go to the primary
+	viper.SetDefault("DB_REPLICA_CHECK_INTERVAL", "10s")
+	viper.SetDefault("DB_REPLICA_CHECK_TIMEOUT", "2s")
 
 	// Set default Redis configuration
 	viper.SetDefault("REDIS_HOST", "localhost")
 	viper.SetDefault("REDIS_PORT", "6379")
+	viper.SetDefault("REDIS_PASS", "")
 
 	// Set default Auth configuration
-	viper.SetDefault("REFRESH_TOKEN_SECRET", "refresh_token_secret")
+	viper.SetDefault("REFRESH_TOKEN_SECRET", defaultRefreshTokenSecret)
 	viper.SetDefault("PRIVATE_KEY_FILE", "./rsa_private.pem")
 	viper.SetDefault("PUBLIC_KEY_FILE", "./rsa_public.pem")
 	viper.SetDefault("ID_TOKEN_EXP", "900")         // 15 minutes
 	viper.SetDefault("REFRESH_TOKEN_EXP", "259200") // 3 days
 
+	// Set default Notification configuration
+	// Delivery channels without credentials only log notifications
+	viper.SetDefault("NOTIFY_HTTP_TIMEOUT", "10s")
+	viper.SetDefault("NOTIFY_SEND_TIMEOUT", "15s") // per channel, including SMTP
+	viper.SetDefault("SMTP_HOST", "")
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("SMTP_USER", "")
+	viper.SetDefault("SMTP_PASS", "")
+	viper.SetDefault("SMTP_FROM", "no-reply@seternak.id")
+	viper.SetDefault("FCM_URL", "https://fcm.googleapis.com/fcm/send")
+	viper.SetDefault("FCM_SERVER_KEY", "")
+	viper.SetDefault("WHATSAPP_URL", "https://graph.facebook.com/v18.0")
+	viper.SetDefault("WHATSAPP_PHONE_NUMBER_ID", "")
+	viper.SetDefault("WHATSAPP_TOKEN", "")
+
+	// Set default Tracing configuration
+	// TRACING_EXPORTER is one of none, stdout or otlp
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_SERVICE_NAME", "seternak-backend")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	viper.SetDefault("TRACING_OTLP_ENDPOINT", "localhost:4318")
+	viper.SetDefault("TRACING_OTLP_INSECURE", false)
+}
+
+// GetSettings retrieves the validated application settings from the Config struct.
+// Returns a pointer to the Settings instance.
+func (c *Config) GetSettings() *Settings {
+	return c.settings
 }
 
 // setLogger initializes from logger utils package.
@@ -110,6 +213,49 @@ func (c *Config) GetLogger() *zap.Logger {
 	return c.zapLogger
 }
 
+// setTracer initializes the OpenTelemetry tracer provider with the exporter set by TRACING_EXPORTER
+// and sets it as the global provider, along with the W3C trace context propagator.
+// It logs a fatal error and exits if the exporter initialization fails.
+func (c *Config) setTracer() {
+	l := c.zapLogger
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		settings = c.settings.Tracing
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch name := settings.Exporter; name {
+	case "", "none":
+		return
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "otlp":
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(settings.OTLPEndpoint)}
+		if settings.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	default:
+		err = fmt.Errorf("unknown tracing exporter %q", name)
+	}
+	if err != nil {
+		l.Fatal("Error initializing tracing exporter", zap.Error(err))
+	}
+
+	c.tracer = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(settings.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(settings.ServiceName),
+			semconv.DeploymentEnvironment(c.settings.App.Env),
+		)),
+	)
+	otel.SetTracerProvider(c.tracer)
+}
+
 // setFiberConfig initializes Fiber's configuration with custom settings.
 // Updates the Config struct fiber field with the new settings.
 func (c *Config) setFiberConfig() {
@@ -178,22 +324,13 @@ func (c *Config) fiberErrorHandler(ctx *fiber.Ctx, err error) error {
 // setDB initializes and configures the database connection using GORM with PostgreSQL.
 // It logs a fatal error and exits if the database initialization fails.
 func (c *Config) setDB() {
-	var (
-		l      = c.zapLogger
-		pgHost = viper.GetString("PG_HOST")
-		pgPort = viper.GetString("PG_PORT")
-		pgUser = viper.GetString("PG_USER")
-		pgPass = viper.GetString("PG_PASS")
-		pgDB   = viper.GetString("PG_DB")
-		pgSSL  = viper.GetString("PG_SSL")
-	)
+	l := c.zapLogger
 
 	loggerDB := zapgorm2.New(l)
 	loggerDB.SetAsDefault()
 
 	l.Info("Connecting to Postgres...")
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", pgHost, pgPort, pgUser, pgPass, pgDB, pgSSL)
-	gormPrepared, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	gormPrepared, err := gorm.Open(postgres.Open(c.settings.DB.DSN()), &gorm.Config{
 		SkipDefaultTransaction: true,
 		PrepareStmt:            true,
 		Logger:                 loggerDB,
@@ -202,9 +339,16 @@ func (c *Config) setDB() {
 		log.Fatalf("Error initializing DB, %v", err)
 	}
 
-	// Register db resolver
+	// Register db resolver, sending reads to the read replicas if any
+	resolverConfig := dbresolver.Config{}
+	if len(c.settings.DB.ReplicaDSNs) > 0 {
+		c.setReplicas()
+		resolverConfig.Replicas = c.replicas.Dialectors()
+		resolverConfig.Policy = c.replicas
+	}
+
 	err = gormPrepared.Use(
-		dbresolver.Register(dbresolver.Config{}).
+		dbresolver.Register(resolverConfig).
 			SetConnMaxIdleTime(time.Hour).
 			SetConnMaxLifetime(24 * time.Hour).
 			SetMaxIdleConns(100).
@@ -214,13 +358,53 @@ func (c *Config) setDB() {
 		log.Fatalf("Error setting up db resolver, %v", err)
 	}
 
-	if viper.GetString("APP_ENV") != consts.ProductionMode {
+	if c.replicas != nil {
+		if err = gormPrepared.Use(c.replicas); err != nil {
+			log.Fatalf("Error setting up replica router, %v", err)
+		}
+		c.replicas.Start()
+	}
+
+	if !c.settings.App.Production() {
 		gormPrepared = gormPrepared.Debug()
 	}
 
 	c.db = gormPrepared
 }
 
+// setReplicas opens the connections to the read replicas and initializes their router.
+// It logs a fatal error and exits if a replica connection cannot be opened.
+func (c *Config) setReplicas() {
+	l := c.zapLogger
+	settings := c.settings.DB
+
+	replicas := make([]*replica.Replica, 0, len(settings.ReplicaDSNs))
+	for _, dsn := range settings.ReplicaDSNs {
+		pgConfig, err := pgconn.ParseConfig(dsn)
+		if err != nil {
+			log.Fatalf("Error parsing replica DSN, %v", err)
+		}
+
+		db, err := sql.Open("pgx", dsn)
+		if err != nil {
+			log.Fatalf("Error opening replica connection, %v", err)
+		}
+
+		replicas = append(replicas, &replica.Replica{
+			Name: fmt.Sprintf("%s:%d", pgConfig.Host, pgConfig.Port),
+			DB:   db,
+		})
+	}
+
+	l.Info("Routing reads to read replicas", zap.Int("replicas", len(replicas)), zap.Bool("readYourWrites", settings.ReadYourWrites))
+	c.replicas = replica.NewRouter(&replica.RouterConfig{
+		Replicas:       replicas,
+		ReadYourWrites: settings.ReadYourWrites,
+		CheckInterval:  settings.ReplicaCheckInterval,
+		CheckTimeout:   settings.ReplicaCheckTimeout,
+	})
+}
+
 // GetDB retrieves the GORM database instance from the Config struct.
 // Returns a pointer to the gorm.DB instance.
 func (c *Config) GetDB() *gorm.DB {
@@ -230,16 +414,12 @@ func (c *Config) GetDB() *gorm.DB {
 // setRedis initializes and configures the Redis connection.
 // It logs a fatal error and exits if the Redis initialization fails.
 func (c *Config) setRedis() {
-	var (
-		l         = c.zapLogger
-		redisHost = viper.GetString("REDIS_HOST")
-		redisPort = viper.GetString("REDIS_PORT")
-	)
+	l := c.zapLogger
 
 	l.Info("Connecting to Redis...")
 	redisClient := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", redisHost, redisPort),
-		Password: "",
+		Addr:     c.settings.Redis.Addr(),
+		Password: c.settings.Redis.Password,
 		DB:       0,
 	})
 
@@ -262,7 +442,7 @@ func (c *Config) GetRedis() *redis.Client {
 func (c *Config) setRSAKeys() {
 	l := c.zapLogger
 
-	privateKeyFile, err := os.ReadFile(viper.GetString("PRIVATE_KEY_FILE"))
+	privateKeyFile, err := os.ReadFile(c.settings.Auth.PrivateKeyFile)
 	if err != nil {
 		l.Fatal("Error reading private key file", zap.Error(err))
 	}
@@ -274,7 +454,7 @@ func (c *Config) setRSAKeys() {
 
 	c.privateKey = privateKey
 
-	publicKeyFile, err := os.ReadFile(viper.GetString("PUBLIC_KEY_FILE"))
+	publicKeyFile, err := os.ReadFile(c.settings.Auth.PublicKeyFile)
 	if err != nil {
 		l.Fatal("Error reading public key file", zap.Error(err))
 	}
@@ -309,9 +489,29 @@ func (c *Config) Close() error {
 		return err
 	}
 
-	if err := c.redisClient.Close(); err != nil {
-		l.Error("Error closing Redis connection", zap.Error(err))
-		return err
+	if c.replicas != nil {
+		if err := c.replicas.Close(); err != nil {
+			l.Error("Error closing replica connections", zap.Error(err))
+			return err
+		}
+	}
+
+	if c.redisClient != nil {
+		if err := c.redisClient.Close(); err != nil {
+			l.Error("Error closing Redis connection", zap.Error(err))
+			return err
+		}
+	}
+
+	// Flush the spans still buffered in the batcher
+	if c.tracer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := c.tracer.Shutdown(ctx); err != nil {
+			l.Error("Error shutting down tracer provider", zap.Error(err))
+			return err
+		}
 	}
 
 	return nil
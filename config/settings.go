@@ -0,0 +1,330 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/j03hanafi/seternak-backend/utils/consts"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
+	"os"
+	"strings"
+	"time"
+)
+
+// Default secrets, only fit for local development
+const (
+	defaultPGPass             = "password"
+	defaultRefreshTokenSecret = "refresh_token_secret"
+)
+
+// secretKeys are the settings that may be read from a file named by the same key suffixed
+// with _FILE, so Docker and Kubernetes secrets never have to be passed as plain variables
+var secretKeys = []string{"PG_PASS", "PG_REPLICA_DSNS", "REDIS_PASS", "REFRESH_TOKEN_SECRET", "SMTP_PASS", "FCM_SERVER_KEY", "WHATSAPP_TOKEN"}
+
+// Settings holds the typed application settings, each field mapped to the
+// environment variable, or .env entry, of the same name.
+type Settings struct {
+	App     AppSettings     `mapstructure:",squash"`
+	Log     LogSettings     `mapstructure:",squash"`
+	DB      DBSettings      `mapstructure:",squash"`
+	Redis   RedisSettings   `mapstructure:",squash"`
+	Auth    AuthSettings    `mapstructure:",squash"`
+	Notify  NotifySettings  `mapstructure:",squash"`
+	Tracing TracingSettings `mapstructure:",squash"`
+}
+
+// AppSettings holds the settings of the HTTP server
+type AppSettings struct {
+	Env                string        `mapstructure:"APP_ENV"`
+	APIURL             string        `mapstructure:"API_URL"`
+	ListenAddr         string        `mapstructure:"LISTEN_ADDR"`
+	HealthCheckTimeout time.Duration `mapstructure:"HEALTH_CHECK_TIMEOUT"`
+	ShutdownDrainDelay time.Duration `mapstructure:"SHUTDOWN_DRAIN_DELAY"`
+}
+
+// Production reports whether the application runs in production mode
+func (s AppSettings) Production() bool {
+	return s.Env == consts.ProductionMode
+}
+
+// LogSettings holds the settings of the logger, which reads them itself as it starts
+// before the settings are loaded. They are part of Settings to be validated.
+type LogSettings struct {
+	Level              string `mapstructure:"LOG_LEVEL"`
+	Format             string `mapstructure:"LOG_FORMAT"`
+	File               string `mapstructure:"LOG_FILE"`
+	FileMaxSize        int    `mapstructure:"LOG_FILE_MAX_SIZE"`
+	FileMaxAge         int    `mapstructure:"LOG_FILE_MAX_AGE"`
+	FileMaxBackups     int    `mapstructure:"LOG_FILE_MAX_BACKUPS"`
+	FileCompress       bool   `mapstructure:"LOG_FILE_COMPRESS"`
+	Sampling           bool   `mapstructure:"LOG_SAMPLING"`
+	SamplingInitial    int    `mapstructure:"LOG_SAMPLING_INITIAL"`
+	SamplingThereafter int    `mapstructure:"LOG_SAMPLING_THEREAFTER"`
+}
+
+// DBSettings holds the settings of the Postgres connection and migrations
+type DBSettings struct {
+	Host                 string        `mapstructure:"PG_HOST"`
+	Port                 string        `mapstructure:"PG_PORT"`
+	User                 string        `mapstructure:"PG_USER"`
+	Password             string        `mapstructure:"PG_PASS"`
+	Name                 string        `mapstructure:"PG_DB"`
+	SSLMode              string        `mapstructure:"PG_SSL"`
+	AutoMigrate          bool          `mapstructure:"DB_AUTO_MIGRATE"`
+	MigrateLockTimeout   time.Duration `mapstructure:"DB_MIGRATE_LOCK_TIMEOUT"`
+	ReplicaDSNs          []string      `mapstructure:"PG_REPLICA_DSNS"`
+	ReadYourWrites       bool          `mapstructure:"DB_READ_YOUR_WRITES"`
+	ReplicaCheckInterval time.Duration `mapstructure:"DB_REPLICA_CHECK_INTERVAL"`
+	ReplicaCheckTimeout  time.Duration `mapstructure:"DB_REPLICA_CHECK_TIMEOUT"`
+}
+
+// DSN builds the Postgres connection string
+func (s DBSettings) DSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", s.Host, s.Port, s.User, s.Password, s.Name, s.SSLMode)
+}
+
+// RedisSettings holds the settings of the Redis connection
+type RedisSettings struct {
+	Host     string `mapstructure:"REDIS_HOST"`
+	Port     string `mapstructure:"REDIS_PORT"`
+	Password string `mapstructure:"REDIS_PASS"`
+}
+
+// Addr returns the address of the Redis server
+func (s RedisSettings) Addr() string {
+	return fmt.Sprintf("%s:%s", s.Host, s.Port)
+}
+
+// AuthSettings holds the settings for signing and verifying tokens
+type AuthSettings struct {
+	RefreshTokenSecret string `mapstructure:"REFRESH_TOKEN_SECRET"`
+	PrivateKeyFile     string `mapstructure:"PRIVATE_KEY_FILE"`
+	PublicKeyFile      string `mapstructure:"PUBLIC_KEY_FILE"`
+	IDTokenExp         int64  `mapstructure:"ID_TOKEN_EXP"`      // seconds
+	RefreshTokenExp    int64  `mapstructure:"REFRESH_TOKEN_EXP"` // seconds
+}
+
+// NotifySettings holds the credentials of the notification delivery channels
+type NotifySettings struct {
+	HTTPTimeout           time.Duration `mapstructure:"NOTIFY_HTTP_TIMEOUT"`
+	SendTimeout           time.Duration `mapstructure:"NOTIFY_SEND_TIMEOUT"`
+	SMTPHost              string        `mapstructure:"SMTP_HOST"`
+	SMTPPort              string        `mapstructure:"SMTP_PORT"`
+	SMTPUser              string        `mapstructure:"SMTP_USER"`
+	SMTPPass              string        `mapstructure:"SMTP_PASS"`
+	SMTPFrom              string        `mapstructure:"SMTP_FROM"`
+	FCMURL                string        `mapstructure:"FCM_URL"`
+	FCMServerKey          string        `mapstructure:"FCM_SERVER_KEY"`
+	WhatsAppURL           string        `mapstructure:"WHATSAPP_URL"`
+	WhatsAppPhoneNumberID string        `mapstructure:"WHATSAPP_PHONE_NUMBER_ID"`
+	WhatsAppToken         string        `mapstructure:"WHATSAPP_TOKEN"`
+}
+
+// TracingSettings holds the settings of the OpenTelemetry exporter
+type TracingSettings struct {
+	Exporter     string  `mapstructure:"TRACING_EXPORTER"`
+	ServiceName  string  `mapstructure:"TRACING_SERVICE_NAME"`
+	SampleRatio  float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+	OTLPEndpoint string  `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	OTLPInsecure bool    `mapstructure:"TRACING_OTLP_INSECURE"`
+}
+
+// LoadSettings loads the configuration with Load, reads the secrets given as *_FILE
+// variables and decodes everything into Settings. The settings are not validated.
+// Returns an error if a secret file cannot be read or a value has the wrong type.
+func LoadSettings() (*Settings, error) {
+	Load()
+
+	var errs []error
+	for _, key := range secretKeys {
+		file := viper.GetString(key + "_FILE")
+		if file == "" {
+			continue
+		}
+
+		secret, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_FILE: %w", key, err))
+			continue
+		}
+
+		// Secret files usually end with a newline that is not part of the secret
+		viper.Set(key, strings.TrimRight(string(secret), "\r\n"))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	settings := new(Settings)
+	if err := viper.Unmarshal(settings); err != nil {
+		return nil, fmt.Errorf("error decoding settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Validate checks every setting and reports all the invalid ones at once, one per line.
+// In production it also refuses the default secrets meant for local development.
+func (s *Settings) Validate() error {
+	production := s.App.Production()
+
+	return errors.Join(
+		s.App.validate(),
+		s.Log.validate(),
+		s.DB.validate(production),
+		s.Redis.validate(),
+		s.Auth.validate(production),
+		s.Notify.validate(),
+		s.Tracing.validate(),
+	)
+}
+
+// ValidateDB checks the settings needed to work on the database alone,
+// for commands that do not serve HTTP requests.
+func (s *Settings) ValidateDB() error {
+	return errors.Join(s.App.validate(), s.DB.validate(s.App.Production()))
+}
+
+// validate checks the server settings
+func (s AppSettings) validate() error {
+	return errors.Join(
+		field("APP_ENV", s.Env, validation.Required, validation.In(consts.DevelopmentMode, consts.ProductionMode, consts.TestMode)),
+		field("API_URL", s.APIURL, validation.Required, validation.By(startsWithSlash)),
+		field("LISTEN_ADDR", s.ListenAddr, validation.Required),
+		field("HEALTH_CHECK_TIMEOUT", s.HealthCheckTimeout, validation.Required, validation.Min(time.Duration(0))),
+		field("SHUTDOWN_DRAIN_DELAY", s.ShutdownDrainDelay, validation.Min(time.Duration(0))),
+	)
+}
+
+// validate checks the logger settings
+func (s LogSettings) validate() error {
+	return errors.Join(
+		field("LOG_LEVEL", s.Level, validation.By(isLogLevel)),
+		field("LOG_FORMAT", s.Format, validation.Required, validation.In(logger.FormatConsole, logger.FormatJSON)),
+		field("LOG_FILE_MAX_SIZE", s.FileMaxSize, when(s.File != logger.FileNone, validation.Required, validation.Min(1))...),
+		field("LOG_FILE_MAX_AGE", s.FileMaxAge, validation.Min(0)),
+		field("LOG_FILE_MAX_BACKUPS", s.FileMaxBackups, validation.Min(0)),
+		field("LOG_SAMPLING_INITIAL", s.SamplingInitial, when(s.Sampling, validation.Required, validation.Min(1))...),
+		field("LOG_SAMPLING_THEREAFTER", s.SamplingThereafter, when(s.Sampling, validation.Required, validation.Min(1))...),
+	)
+}
+
+// validate checks the Postgres settings
+func (s DBSettings) validate(production bool) error {
+	return errors.Join(
+		field("PG_HOST", s.Host, validation.Required),
+		field("PG_PORT", s.Port, validation.Required, is.Port),
+		field("PG_USER", s.User, validation.Required),
+		field("PG_PASS", s.Password, when(production, validation.NotIn(defaultPGPass).Error("must not be the default password in production"))...),
+		field("PG_DB", s.Name, validation.Required),
+		field("PG_SSL", s.SSLMode, validation.Required, validation.In("disable", "allow", "prefer", "require", "verify-ca", "verify-full")),
+		field("DB_MIGRATE_LOCK_TIMEOUT", s.MigrateLockTimeout, validation.Required, validation.Min(time.Duration(0))),
+		field("PG_REPLICA_DSNS", s.ReplicaDSNs, validation.By(arePGDSNs)),
+		field("DB_REPLICA_CHECK_INTERVAL", s.ReplicaCheckInterval, when(len(s.ReplicaDSNs) > 0, validation.Required, validation.Min(time.Duration(0)))...),
+		field("DB_REPLICA_CHECK_TIMEOUT", s.ReplicaCheckTimeout, when(len(s.ReplicaDSNs) > 0, validation.Required, validation.Min(time.Duration(0)))...),
+	)
+}
+
+// validate checks the Redis settings
+func (s RedisSettings) validate() error {
+	return errors.Join(
+		field("REDIS_HOST", s.Host, validation.Required),
+		field("REDIS_PORT", s.Port, validation.Required, is.Port),
+	)
+}
+
+// validate checks the token settings, including that the key files can be read
+func (s AuthSettings) validate(production bool) error {
+	return errors.Join(
+		field("REFRESH_TOKEN_SECRET", s.RefreshTokenSecret, validation.Required),
+		field("REFRESH_TOKEN_SECRET", s.RefreshTokenSecret, when(production, validation.NotIn(defaultRefreshTokenSecret).Error("must not be the default secret in production"))...),
+		field("PRIVATE_KEY_FILE", s.PrivateKeyFile, validation.Required, validation.By(isReadableFile)),
+		field("PUBLIC_KEY_FILE", s.PublicKeyFile, validation.Required, validation.By(isReadableFile)),
+		field("ID_TOKEN_EXP", s.IDTokenExp, validation.Required, validation.Min(int64(1))),
+		field("REFRESH_TOKEN_EXP", s.RefreshTokenExp, validation.Required, validation.Min(s.IDTokenExp).Error("must not be shorter than ID_TOKEN_EXP")),
+	)
+}
+
+// validate checks the notification settings of the channels that are configured
+func (s NotifySettings) validate() error {
+	return errors.Join(
+		field("NOTIFY_HTTP_TIMEOUT", s.HTTPTimeout, validation.Required, validation.Min(time.Duration(0))),
+		field("NOTIFY_SEND_TIMEOUT", s.SendTimeout, validation.Required, validation.Min(time.Duration(0))),
+		field("SMTP_PORT", s.SMTPPort, when(s.SMTPHost != "", validation.Required, is.Port)...),
+		field("SMTP_FROM", s.SMTPFrom, when(s.SMTPHost != "", validation.Required, is.Email)...),
+		field("FCM_URL", s.FCMURL, when(s.FCMServerKey != "", validation.Required, is.URL)...),
+		field("WHATSAPP_URL", s.WhatsAppURL, when(s.WhatsAppToken != "", validation.Required, is.URL)...),
+		field("WHATSAPP_PHONE_NUMBER_ID", s.WhatsAppPhoneNumberID, when(s.WhatsAppToken != "", validation.Required)...),
+	)
+}
+
+// validate checks the tracing settings
+func (s TracingSettings) validate() error {
+	return errors.Join(
+		field("TRACING_EXPORTER", s.Exporter, validation.In("", "none", "stdout", "otlp")),
+		field("TRACING_SERVICE_NAME", s.ServiceName, when(s.Exporter == "otlp" || s.Exporter == "stdout", validation.Required)...),
+		field("TRACING_SAMPLE_RATIO", s.SampleRatio, validation.Min(0.0), validation.Max(1.0)),
+		field("TRACING_OTLP_ENDPOINT", s.OTLPEndpoint, when(s.Exporter == "otlp", validation.Required)...),
+	)
+}
+
+// field validates a single setting, naming it by its key in the returned error
+func field(key string, value any, rules ...validation.Rule) error {
+	if err := validation.Validate(value, rules...); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// when returns rules if cond holds, for settings that only matter in some setups
+func when(cond bool, rules ...validation.Rule) []validation.Rule {
+	if !cond {
+		return nil
+	}
+	return rules
+}
+
+// startsWithSlash checks that a path is absolute
+func startsWithSlash(value any) error {
+	if s, _ := value.(string); !strings.HasPrefix(s, "/") {
+		return errors.New("must start with /")
+	}
+	return nil
+}
+
+// isLogLevel checks that a non-empty value names a zap level
+func isLogLevel(value any) error {
+	s, _ := value.(string)
+	if s == "" {
+		return nil
+	}
+	_, err := zapcore.ParseLevel(s)
+	return err
+}
+
+// arePGDSNs checks that every entry is a Postgres connection string, without
+// repeating the entry as it holds a password
+func arePGDSNs(value any) error {
+	dsns, _ := value.([]string)
+	for i, dsn := range dsns {
+		if _, err := pgconn.ParseConfig(dsn); err != nil {
+			return fmt.Errorf("entry %d is not a valid Postgres connection string", i+1)
+		}
+	}
+	return nil
+}
+
+// isReadableFile checks that a file exists and can be read
+func isReadableFile(value any) error {
+	path, _ := value.(string)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"github.com/j03hanafi/seternak-backend/utils/consts"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadTestSettings loads the settings from defaults and the environment alone
+func loadTestSettings(t *testing.T) *Settings {
+	t.Helper()
+	t.Cleanup(viper.Reset)
+
+	settings, err := LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return settings
+}
+
+func TestLoadSettings(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "refresh_token_secret")
+	if err := os.WriteFile(secret, []byte("s3cr3t-from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("REFRESH_TOKEN_SECRET_FILE", secret)
+	t.Setenv("ID_TOKEN_EXP", "60")
+	t.Setenv("HEALTH_CHECK_TIMEOUT", "3s")
+
+	settings := loadTestSettings(t)
+
+	assert.Equal(t, "s3cr3t-from-file", settings.Auth.RefreshTokenSecret)
+	assert.EqualValues(t, 60, settings.Auth.IDTokenExp)
+	assert.Equal(t, "3s", settings.App.HealthCheckTimeout.String())
+	assert.Equal(t, "6379", settings.Redis.Port)
+}
+
+func TestSettingsValidate(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "rsa.pem")
+	if err := os.WriteFile(key, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PRIVATE_KEY_FILE", key)
+	t.Setenv("PUBLIC_KEY_FILE", key)
+
+	t.Run("defaults are valid outside production", func(t *testing.T) {
+		t.Setenv("APP_ENV", consts.DevelopmentMode)
+
+		assert.NoError(t, loadTestSettings(t).Validate())
+	})
+
+	t.Run("default secrets are refused in production", func(t *testing.T) {
+		t.Setenv("APP_ENV", consts.ProductionMode)
+		t.Setenv("PG_PASS", defaultPGPass)
+		t.Setenv("REFRESH_TOKEN_SECRET", defaultRefreshTokenSecret)
+
+		assert.EqualError(t, loadTestSettings(t).Validate(),
+			"PG_PASS: must not be the default password in production\n"+
+				"REFRESH_TOKEN_SECRET: must not be the default secret in production")
+	})
+
+	t.Run("every invalid setting is reported", func(t *testing.T) {
+		t.Setenv("APP_ENV", consts.DevelopmentMode)
+		t.Setenv("PG_PORT", "postgres")
+		missing := filepath.Join(t.TempDir(), "missing.pem")
+		t.Setenv("PUBLIC_KEY_FILE", missing)
+		t.Setenv("TRACING_EXPORTER", "jaeger")
+
+		assert.EqualError(t, loadTestSettings(t).Validate(),
+			"PG_PORT: must be a valid port number\n"+
+				"PUBLIC_KEY_FILE: open "+missing+": no such file or directory\n"+
+				"TRACING_EXPORTER: must be a valid value")
+	})
+}
@@ -18,6 +18,7 @@ const (
 	NotFound      Type = "E002" // For not finding resource
 	Authorization Type = "E003" // Authentication Failures
 	BadRequest    Type = "E004" // Validation errors / BadInput
+	Forbidden     Type = "E005" // Authenticated but not allowed - 403
 )
 
 // Error is the standard error interface
@@ -63,6 +64,8 @@ func (e Error) Status() int {
 		return http.StatusUnauthorized
 	case BadRequest:
 		return http.StatusBadRequest
+	case Forbidden:
+		return http.StatusForbidden
 	default:
 		return http.StatusInternalServerError
 	}
@@ -138,3 +141,15 @@ func NewBadRequest(err error, reason ...string) *Error {
 		Message: message,
 	})
 }
+
+// NewForbidden returns a 403 Forbidden Error
+func NewForbidden(err error, reason ...string) *Error {
+	message := "Forbidden"
+	if len(reason) > 0 {
+		message = fmt.Sprintf("Forbidden. Reason: %v", reason[0])
+	}
+	return newError(err, &Error{
+		Type:    Forbidden,
+		Message: message,
+	})
+}
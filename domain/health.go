@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Set of dependency health statuses
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// DependencyHealth defines the result of checking a single dependency and its json representation.
+// Error is logged but never serialized, as it may reveal internal hosts to unauthenticated callers.
+type DependencyHealth struct {
+	Name      string        `json:"name"`
+	Status    string        `json:"status"`
+	Latency   time.Duration `json:"-"`
+	LatencyMs float64       `json:"latency_ms"`
+	Error     string        `json:"-"`
+}
+
+// HealthService defines methods the handler layer expects
+// any service it interacts with to implement
+type HealthService interface {
+
+	// Check pings every dependency concurrently, each bounded by the service timeout.
+	// Returns the result per dependency and whether all of them are up.
+	Check(ctx context.Context) ([]*DependencyHealth, bool)
+}
+
+// HealthRepository defines methods the service layer expects
+// any dependency it checks to implement
+type HealthRepository interface {
+
+	// Name returns the name the dependency is reported under.
+	Name() string
+
+	// Ping checks that the dependency is reachable.
+	// Returns an error if it is not or if ctx expires first.
+	Ping(ctx context.Context) error
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "github.com/oklog/ulid/v2"
+
+// FilterOperator defines how a Filter compares a column against its value
+type FilterOperator string
+
+// Set of valid filter operators
+const (
+	FilterEqual  FilterOperator = "eq"      // column = value
+	FilterIsNull FilterOperator = "is_null" // column IS NULL when value is true, IS NOT NULL otherwise
+)
+
+// Filter restricts a list to the rows whose column matches the value
+type Filter struct {
+	Column   string
+	Operator FilterOperator
+	Value    any
+}
+
+// ListQuery holds the paging, filtering and sorting options of a list request.
+// Columns are expected to be whitelisted by the handler layer before reaching a repository.
+type ListQuery struct {
+	// Cursor is the ID of the last item of the previous page, zero for the first page
+	Cursor  ulid.ULID
+	Limit   int
+	Filters []Filter
+	Sort    string
+	Desc    bool
+}
+
+// Page is a single page of a list along with the cursor of the next page
+type Page[T any] struct {
+	Items []T
+	// NextCursor is zero when there are no more pages
+	NextCursor ulid.ULID
+	Total      int64
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHealthRepository struct {
+	mock.Mock
+}
+
+func (m *MockHealthRepository) Name() string {
+	args := m.Called()
+
+	return args.String(0)
+}
+
+func (m *MockHealthRepository) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+
+	var r0 error
+	if args.Get(0) != nil {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,105 @@
+package mocks
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	args := m.Called(ctx, n)
+
+	var r0 error
+	if args.Get(0) != nil {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
+
+func (m *MockNotificationRepository) FindByUser(ctx context.Context, uid ulid.ULID, q *domain.ListQuery) (*domain.Page[*domain.Notification], error) {
+	args := m.Called(ctx, uid, q)
+
+	var r0 *domain.Page[*domain.Notification]
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*domain.Page[*domain.Notification])
+	}
+
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, uid, id ulid.ULID, readAt time.Time) error {
+	args := m.Called(ctx, uid, id, readAt)
+
+	var r0 error
+	if args.Get(0) != nil {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, uid ulid.ULID, readAt time.Time) error {
+	args := m.Called(ctx, uid, readAt)
+
+	var r0 error
+	if args.Get(0) != nil {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
+
+func (m *MockNotificationRepository) FindPreference(ctx context.Context, uid ulid.ULID) (*domain.NotificationPreference, error) {
+	args := m.Called(ctx, uid)
+
+	var r0 *domain.NotificationPreference
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*domain.NotificationPreference)
+	}
+
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (m *MockNotificationRepository) SavePreference(ctx context.Context, p *domain.NotificationPreference) error {
+	args := m.Called(ctx, p)
+
+	var r0 error
+	if args.Get(0) != nil {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, uid ulid.ULID) (int64, error) {
+	args := m.Called(ctx, uid)
+
+	var r0 int64
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(int64)
+	}
+
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
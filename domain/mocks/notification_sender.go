@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationSender struct {
+	mock.Mock
+}
+
+func (m *MockNotificationSender) Channel() domain.NotificationChannel {
+	args := m.Called()
+
+	return args.Get(0).(domain.NotificationChannel)
+}
+
+func (m *MockNotificationSender) Send(ctx context.Context, u *domain.User, p *domain.NotificationPreference, n *domain.Notification) error {
+	args := m.Called(ctx, u, p, n)
+
+	var r0 error
+	if args.Get(0) != nil {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
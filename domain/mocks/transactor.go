@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransactor struct {
+	mock.Mock
+}
+
+// WithinTransaction runs fn with ctx, unless the expectation returns an error
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx)
+
+	if args.Get(0) != nil {
+		return args.Error(0)
+	}
+
+	return fn(ctx)
+}
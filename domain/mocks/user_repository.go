@@ -54,3 +54,14 @@ func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*do
 	return r0, r1
 
 }
+
+func (m *MockUserRepository) UpdateAdmin(ctx context.Context, uid ulid.ULID, admin bool) error {
+	args := m.Called(ctx, uid, admin)
+
+	var r0 error
+	if args.Get(0) != nil {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
@@ -60,3 +60,25 @@ func (m *MockUserService) LogIn(ctx context.Context, u *domain.User) error {
 	return r0
 
 }
+
+func (m *MockUserService) SetAdmin(ctx context.Context, uid ulid.ULID, admin bool) error {
+	args := m.Called(ctx, uid, admin)
+
+	var r0 error
+	if args.Get(0) != nil {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
+
+func (m *MockUserService) CreateAdmin(ctx context.Context, u *domain.User) error {
+	args := m.Called(ctx, u)
+
+	var r0 error
+	if args.Get(0) != nil {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
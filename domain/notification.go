@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"context"
+	"github.com/oklog/ulid/v2"
+	"time"
+)
+
+// NotificationCategory groups notifications so users can choose
+// how each kind of notification reaches them
+type NotificationCategory string
+
+// Set of valid notification categories
+const (
+	NotificationCategorySystem     NotificationCategory = "system"
+	NotificationCategoryTask       NotificationCategory = "task"
+	NotificationCategoryInventory  NotificationCategory = "inventory"
+	NotificationCategoryInvitation NotificationCategory = "invitation"
+)
+
+// NotificationCategories lists every valid NotificationCategory
+var NotificationCategories = []NotificationCategory{
+	NotificationCategorySystem,
+	NotificationCategoryTask,
+	NotificationCategoryInventory,
+	NotificationCategoryInvitation,
+}
+
+// NotificationChannel identifies a medium a notification is delivered through
+type NotificationChannel string
+
+// Set of valid notification channels
+const (
+	NotificationChannelInApp    NotificationChannel = "in_app"
+	NotificationChannelEmail    NotificationChannel = "email"
+	NotificationChannelPush     NotificationChannel = "push"
+	NotificationChannelWhatsApp NotificationChannel = "whatsapp"
+)
+
+// NotificationChannels lists every valid NotificationChannel
+var NotificationChannels = []NotificationChannel{
+	NotificationChannelInApp,
+	NotificationChannelEmail,
+	NotificationChannelPush,
+	NotificationChannelWhatsApp,
+}
+
+// Notification defines domain model of an inbox entry and its json representation
+type Notification struct {
+	ID        ulid.ULID            `json:"id"`
+	UserUID   ulid.ULID            `json:"-"`
+	Category  NotificationCategory `json:"category"`
+	Title     string               `json:"title"`
+	Body      string               `json:"body"`
+	ReadAt    *time.Time           `json:"read_at"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// NotificationPreference holds a user's delivery settings, that is which
+// channels are enabled per category along with the channel addresses
+type NotificationPreference struct {
+	UserUID     ulid.ULID                                      `json:"-"`
+	PushToken   string                                         `json:"push_token"`
+	PhoneNumber string                                         `json:"phone_number"`
+	Channels    map[NotificationCategory][]NotificationChannel `json:"channels"`
+}
+
+// DefaultNotificationPreference returns the preference used for users
+// who have not saved their own, delivering every category in-app and by email
+func DefaultNotificationPreference(uid ulid.ULID) *NotificationPreference {
+	channels := make(map[NotificationCategory][]NotificationChannel, len(NotificationCategories))
+	for _, category := range NotificationCategories {
+		channels[category] = []NotificationChannel{NotificationChannelInApp, NotificationChannelEmail}
+	}
+
+	return &NotificationPreference{
+		UserUID:  uid,
+		Channels: channels,
+	}
+}
+
+// Enabled reports whether the channel is enabled for the category
+func (p *NotificationPreference) Enabled(category NotificationCategory, channel NotificationChannel) bool {
+	for _, c := range p.Channels[category] {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationService defines methods the handler layer and other services
+// expect any service it interacts with to implement
+type NotificationService interface {
+
+	// Notify stores a notification in the recipient's inbox if they enabled in-app
+	// notifications for its category, and delivers it in the background through every
+	// other channel the recipient enabled for it.
+	// Returns an error only if storing the notification fails.
+	Notify(ctx context.Context, n *Notification) error
+
+	// Wait blocks until the deliveries started by Notify are done, or ctx expires.
+	// Returns an error if deliveries are still pending when ctx expires.
+	Wait(ctx context.Context) error
+
+	// List retrieves a page of the notifications in a user's inbox.
+	// Returns the page or an error if the retrieval process fails.
+	List(ctx context.Context, uid ulid.ULID, q *ListQuery) (*Page[*Notification], error)
+
+	// CountUnread counts the unread notifications in a user's inbox.
+	// Returns the count or an error if the count fails.
+	CountUnread(ctx context.Context, uid ulid.ULID) (int64, error)
+
+	// MarkRead marks a single notification in a user's inbox as read.
+	// Returns a not found error if the user has no such notification.
+	MarkRead(ctx context.Context, uid, id ulid.ULID) error
+
+	// MarkAllRead marks every notification in a user's inbox as read.
+	// Returns an error if the update fails.
+	MarkAllRead(ctx context.Context, uid ulid.ULID) error
+
+	// GetPreference retrieves a user's notification preference, or the default one.
+	// Returns the preference or an error if the retrieval process fails.
+	GetPreference(ctx context.Context, uid ulid.ULID) (*NotificationPreference, error)
+
+	// UpdatePreference replaces a user's notification preference.
+	// Returns an error if the update fails.
+	UpdatePreference(ctx context.Context, p *NotificationPreference) error
+}
+
+// NotificationRepository defines methods the service layer expects
+// any repository it interacts with to implement
+type NotificationRepository interface {
+
+	// Create inserts a new Notification record into the database.
+	// Returns an error if the creation process fails.
+	Create(ctx context.Context, n *Notification) error
+
+	// FindByUser retrieves a page of the notifications of a user.
+	// Returns the page or an error if the query fails.
+	FindByUser(ctx context.Context, uid ulid.ULID, q *ListQuery) (*Page[*Notification], error)
+
+	// CountUnread counts the notifications of a user that have not been read.
+	// Returns the count or an error if the query fails.
+	CountUnread(ctx context.Context, uid ulid.ULID) (int64, error)
+
+	// MarkRead sets the read time of a user's notification.
+	// Returns a not found error if the user has no such notification.
+	MarkRead(ctx context.Context, uid, id ulid.ULID, readAt time.Time) error
+
+	// MarkAllRead sets the read time of every unread notification of a user.
+	// Returns an error if the update fails.
+	MarkAllRead(ctx context.Context, uid ulid.ULID, readAt time.Time) error
+
+	// FindPreference retrieves a user's notification preference.
+	// Returns a not found error if the user has not saved one.
+	FindPreference(ctx context.Context, uid ulid.ULID) (*NotificationPreference, error)
+
+	// SavePreference inserts or replaces a user's notification preference.
+	// Returns an error if the save process fails.
+	SavePreference(ctx context.Context, p *NotificationPreference) error
+}
+
+// NotificationSender defines methods the service layer expects
+// any delivery channel it interacts with to implement
+type NotificationSender interface {
+
+	// Channel returns the channel this sender delivers through.
+	Channel() NotificationChannel
+
+	// Send delivers a notification to a user using the addresses from their preference.
+	// Returns an error if the delivery fails.
+	Send(ctx context.Context, u *User, p *NotificationPreference, n *Notification) error
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "context"
+
+// Transactor defines methods the service layer expects to run a unit of work
+// spanning several repositories atomically
+type Transactor interface {
+
+	// WithinTransaction runs fn in a transaction carried by the ctx passed to fn, so every
+	// repository called with that ctx takes part in it. Nested calls run in a savepoint.
+	// The transaction commits if fn returns nil, and rolls back if fn returns an error or panics.
+	// Returns the error of fn, or an error if the transaction cannot begin or commit.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -11,6 +11,7 @@ type User struct {
 	Email    string    `json:"email"`
 	Password string    `json:"-"`
 	Name     string    `json:"name"`
+	Admin    bool      `json:"admin"`
 }
 
 // UserService defines methods the handler layer expects
@@ -32,6 +33,14 @@ type UserService interface {
 	// Get retrieves a user's details from the database using their unique identifier.
 	// Returns a User object or an error if the user retrieval process fails.
 	Get(ctx context.Context, uid ulid.ULID) (*User, error)
+
+	// SetAdmin grants or revokes a user's administrator rights.
+	// Returns an error if the user does not exist or the update fails.
+	SetAdmin(ctx context.Context, uid ulid.ULID, admin bool) error
+
+	// CreateAdmin registers a new user with administrator rights in a single transaction.
+	// Returns an error, leaving no user behind, if any step fails.
+	CreateAdmin(ctx context.Context, u *User) error
 }
 
 // UserRepository defines methods the service layer expects
@@ -49,4 +58,8 @@ type UserRepository interface {
 	// FindByID retrieves a user from the database using their unique identifier.
 	// Returns a User object or an error if the user is not found.
 	FindByID(ctx context.Context, uid ulid.ULID) (*User, error)
+
+	// UpdateAdmin sets whether a user is an administrator.
+	// Returns an error if the user is not found or the update fails.
+	UpdateAdmin(ctx context.Context, uid ulid.ULID, admin bool) error
 }
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/handler/response"
+	"sync/atomic"
+)
+
+// Health struct holds required services for handler to function
+type Health struct {
+	healthService domain.HealthService
+	draining      atomic.Bool
+}
+
+// HealthHandlerConfig will hold services that will eventually be injected into this
+// handler layer
+type HealthHandlerConfig struct {
+	HealthService domain.HealthService
+}
+
+// NewHealth is a factory function for initializing a Health Handler
+// with its service layer dependencies
+func NewHealth(c *HealthHandlerConfig) *Health {
+	h := new(Health)
+
+	if c.HealthService != nil {
+		h.healthService = c.HealthService
+	}
+
+	return h
+}
+
+// Drain makes readiness fail from now on, so load balancers stop routing
+// new traffic to this instance before it shuts down
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// Liveness handler reports the process is up and able to serve requests.
+// It does not check dependencies, so an outage elsewhere does not get the instance restarted.
+func (h *Health) Liveness(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(response.CustomResponse{
+		HTTPStatusCode: fiber.StatusOK,
+		ResponseData: fiber.Map{
+			"status": domain.HealthStatusUp,
+		},
+	})
+}
+
+// Readiness handler reports whether the instance should receive traffic, checking
+// every dependency. Responds with 503 if any dependency is down or the instance is draining.
+func (h *Health) Readiness(c *fiber.Ctx) error {
+	dependencies, healthy := h.healthService.Check(c.UserContext())
+	draining := h.draining.Load()
+
+	status, statusCode := domain.HealthStatusUp, fiber.StatusOK
+	if !healthy || draining {
+		status, statusCode = domain.HealthStatusDown, fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(statusCode).JSON(response.CustomResponse{
+		HTTPStatusCode: statusCode,
+		ResponseData: fiber.Map{
+			"status":       status,
+			"draining":     draining,
+			"dependencies": dependencies,
+		},
+	})
+}
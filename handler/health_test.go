@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/mocks"
+	"github.com/j03hanafi/seternak-backend/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealth_Readiness(t *testing.T) {
+	postgres := new(mocks.MockHealthRepository)
+	postgres.On("Name").Return("postgres")
+	postgres.On("Ping", mock.Anything).Return(errors.New("dial tcp 10.0.0.12:5432: connect: connection refused"))
+
+	h := NewHealth(&HealthHandlerConfig{
+		HealthService: service.NewHealth(&service.HealthServiceConfig{
+			HealthRepositories: []domain.HealthRepository{postgres},
+		}),
+	})
+
+	app := fiber.New()
+	app.Get("/readyz", h.Readiness)
+
+	res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/readyz", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+
+	assert.Equal(t, fiber.StatusServiceUnavailable, res.StatusCode)
+	assert.Contains(t, string(body), `"name":"postgres","status":"down"`)
+	assert.NotContains(t, string(body), "10.0.0.12")
+	assert.NotContains(t, string(body), "error")
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/handler/request"
+	"github.com/j03hanafi/seternak-backend/handler/response"
+	"github.com/j03hanafi/seternak-backend/utils/consts"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"go.uber.org/zap"
+)
+
+// Log struct holds the level of the application logger for handler to change
+type Log struct {
+	level zap.AtomicLevel
+}
+
+// LogHandlerConfig will hold the logger level that will eventually be injected into this
+// handler layer
+type LogHandlerConfig struct {
+	Level zap.AtomicLevel
+}
+
+// NewLog is a factory function for initializing a Log Handler
+func NewLog(c *LogHandlerConfig) *Log {
+	return &Log{
+		level: c.Level,
+	}
+}
+
+// GetLevel handler returns the current level of the application logger
+func (h *Log) GetLevel(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(response.CustomResponse{
+		HTTPStatusCode: fiber.StatusOK,
+		ResponseData: fiber.Map{
+			"level": h.level.String(),
+		},
+	})
+}
+
+// SetLevel handler changes the level of the application logger, taking effect
+// immediately and lasting until the next restart
+func (h *Log) SetLevel(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	l := logger.FromCtx(ctx)
+
+	user := c.Locals(consts.JWTUserContextKey).(*domain.User)
+
+	// bind request body to UpdateLogLevel struct
+	req := new(request.UpdateLogLevel)
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		return apperrors.NewBadRequest(err)
+	}
+
+	// validate request body
+	if err := req.Validate(); err != nil {
+		l.Error("error validating data",
+			zap.Error(err),
+		)
+		return apperrors.NewBadRequest(err)
+	}
+
+	previous := h.level.String()
+	if err := h.level.UnmarshalText([]byte(req.Level)); err != nil {
+		return apperrors.NewBadRequest(err)
+	}
+
+	// Logged at warn, so the change is recorded at any level it is set to below fatal
+	l.Warn("Log level changed",
+		zap.String("from", previous),
+		zap.String("to", h.level.String()),
+		zap.String("by", user.UID.String()),
+	)
+
+	return c.Status(fiber.StatusOK).JSON(response.CustomResponse{
+		HTTPStatusCode: fiber.StatusOK,
+		ResponseData: fiber.Map{
+			"level": h.level.String(),
+		},
+	})
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/handler/request"
+	"github.com/j03hanafi/seternak-backend/handler/response"
+	"github.com/j03hanafi/seternak-backend/utils/consts"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+)
+
+// Notification struct holds required services for handler to function
+type Notification struct {
+	notificationService domain.NotificationService
+}
+
+// NotificationHandlerConfig will hold services that will eventually be injected into this
+// handler layer
+type NotificationHandlerConfig struct {
+	NotificationService domain.NotificationService
+}
+
+// NewNotification is a factory function for initializing a Notification Handler
+// with its service layer dependencies
+func NewNotification(c *NotificationHandlerConfig) *Notification {
+	n := new(Notification)
+
+	if c.NotificationService != nil {
+		n.notificationService = c.NotificationService
+	}
+
+	return n
+}
+
+// notificationListOptions whitelists the query parameters accepted by List
+var notificationListOptions = request.ListOptions{
+	Filters: map[string]request.FilterField{
+		"category": {Column: "category", Operator: domain.FilterEqual},
+		"unread":   {Column: "read_at", Operator: domain.FilterIsNull},
+	},
+	Sorts: map[string]string{
+		"created_at": "id",
+	},
+	DefaultSort: "id",
+	DefaultDesc: true,
+}
+
+// List handler returns a page of the signed-in user's inbox, newest first, along with
+// the number of unread notifications. Passing ?unread=true only returns unread notifications.
+func (n *Notification) List(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	l := logger.FromCtx(ctx)
+
+	user := c.Locals(consts.JWTUserContextKey).(*domain.User)
+
+	q, err := request.ParseListQuery(c, notificationListOptions)
+	if err != nil {
+		l.Error("error parsing list query",
+			zap.Error(err),
+		)
+		return apperrors.NewBadRequest(err, err.Error())
+	}
+
+	page, err := n.notificationService.List(ctx, user.UID, q)
+	if err != nil {
+		l.Error("Unable to list notifications",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	unread, err := n.notificationService.CountUnread(ctx, user.UID)
+	if err != nil {
+		l.Error("Unable to count unread notifications",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.InboxResponse{
+		PaginatedResponse: response.NewPaginated(fiber.StatusOK, page),
+		Unread:            unread,
+	})
+}
+
+// MarkRead handler marks a single notification as read
+func (n *Notification) MarkRead(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	l := logger.FromCtx(ctx)
+
+	user := c.Locals(consts.JWTUserContextKey).(*domain.User)
+
+	notificationID, err := ulid.Parse(c.Params("id"))
+	if err != nil {
+		l.Error("error parsing notification id",
+			zap.Error(err),
+		)
+		return apperrors.NewBadRequest(err, "Invalid notification id")
+	}
+
+	if err = n.notificationService.MarkRead(ctx, user.UID, notificationID); err != nil {
+		l.Info("Unable to mark notification as read",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.CustomResponse{
+		HTTPStatusCode: fiber.StatusOK,
+		ResponseData:   "Successfully marked notification as read",
+	})
+}
+
+// MarkAllRead handler marks every notification in the inbox as read
+func (n *Notification) MarkAllRead(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	l := logger.FromCtx(ctx)
+
+	user := c.Locals(consts.JWTUserContextKey).(*domain.User)
+
+	if err := n.notificationService.MarkAllRead(ctx, user.UID); err != nil {
+		l.Info("Unable to mark notifications as read",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.CustomResponse{
+		HTTPStatusCode: fiber.StatusOK,
+		ResponseData:   "Successfully marked all notifications as read",
+	})
+}
+
+// GetPreference handler returns the signed-in user's notification preference
+func (n *Notification) GetPreference(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	l := logger.FromCtx(ctx)
+
+	user := c.Locals(consts.JWTUserContextKey).(*domain.User)
+
+	preference, err := n.notificationService.GetPreference(ctx, user.UID)
+	if err != nil {
+		l.Error("Unable to get notification preference",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.CustomResponse{
+		HTTPStatusCode: fiber.StatusOK,
+		ResponseData: fiber.Map{
+			"preference": preference,
+		},
+	})
+}
+
+// UpdatePreference handler replaces the signed-in user's notification preference
+func (n *Notification) UpdatePreference(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	l := logger.FromCtx(ctx)
+
+	user := c.Locals(consts.JWTUserContextKey).(*domain.User)
+
+	// bind request body to UpdateNotificationPreference struct
+	req := new(request.UpdateNotificationPreference)
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		return apperrors.NewBadRequest(err)
+	}
+
+	// validate request body
+	if err := req.Validate(); err != nil {
+		l.Error("error validating data",
+			zap.Error(err),
+		)
+		return apperrors.NewBadRequest(err)
+	}
+
+	preference := &domain.NotificationPreference{
+		UserUID:     user.UID,
+		PushToken:   req.PushToken,
+		PhoneNumber: req.PhoneNumber,
+		Channels:    req.Channels,
+	}
+
+	if err := n.notificationService.UpdatePreference(ctx, preference); err != nil {
+		l.Info("Unable to update notification preference",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.CustomResponse{
+		HTTPStatusCode: fiber.StatusOK,
+		ResponseData: fiber.Map{
+			"preference": preference,
+		},
+	})
+}
@@ -0,0 +1,93 @@
+package request
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/oklog/ulid/v2"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ListOptions whitelists the query parameters a list endpoint accepts.
+// Filters and Sorts map query parameter names to database columns.
+type ListOptions struct {
+	Filters     map[string]FilterField
+	Sorts       map[string]string
+	DefaultSort string
+	DefaultDesc bool
+	MaxLimit    int
+}
+
+// FilterField maps a filter query parameter to a column and the operator used to compare it.
+type FilterField struct {
+	Column   string
+	Operator domain.FilterOperator
+}
+
+// ParseListQuery reads the cursor, limit, sort and whitelisted filter query parameters.
+// Sorting descending is requested by prefixing the sort field with "-", e.g. ?sort=-created_at.
+// Returns an error if any parameter is malformed or not whitelisted.
+func ParseListQuery(c *fiber.Ctx, o ListOptions) (*domain.ListQuery, error) {
+	q := &domain.ListQuery{
+		Limit: defaultListLimit,
+		Sort:  o.DefaultSort,
+		Desc:  o.DefaultDesc,
+	}
+
+	maxLimit := o.MaxLimit
+	if maxLimit <= 0 {
+		maxLimit = maxListLimit
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		id, err := ulid.Parse(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("cursor: %w", err)
+		}
+		q.Cursor = id
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("limit: must be a positive number")
+		}
+		q.Limit = min(n, maxLimit)
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		field, desc := strings.CutPrefix(sort, "-")
+		column, ok := o.Sorts[field]
+		if !ok {
+			return nil, fmt.Errorf("sort: unsupported field %q", field)
+		}
+		q.Sort = column
+		q.Desc = desc
+	}
+
+	for name, field := range o.Filters {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+
+		filter := domain.Filter{Column: field.Column, Operator: field.Operator, Value: value}
+		if field.Operator == domain.FilterIsNull {
+			isNull, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: must be true or false", name)
+			}
+			filter.Value = isNull
+		}
+
+		q.Filters = append(q.Filters, filter)
+	}
+
+	return q, nil
+}
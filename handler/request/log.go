@@ -0,0 +1,25 @@
+package request
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"go.uber.org/zap/zapcore"
+)
+
+// UpdateLogLevel defines the request payload for SetLevel method.
+type UpdateLogLevel struct {
+	Level string `json:"level"`
+}
+
+// Validate validates the UpdateLogLevel request fields.
+func (s UpdateLogLevel) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Level, validation.Required, validation.By(validateLogLevel)),
+	)
+}
+
+// validateLogLevel checks that the value names a zap level, like debug or warn.
+func validateLogLevel(value any) error {
+	level, _ := value.(string)
+	_, err := zapcore.ParseLevel(level)
+	return err
+}
@@ -0,0 +1,65 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/j03hanafi/seternak-backend/domain"
+)
+
+// UpdateNotificationPreference defines the request payload for UpdatePreference method.
+type UpdateNotificationPreference struct {
+	PushToken   string                                                       `json:"push_token"`
+	PhoneNumber string                                                       `json:"phone_number"`
+	Channels    map[domain.NotificationCategory][]domain.NotificationChannel `json:"channels"`
+}
+
+// Validate validates the UpdateNotificationPreference request fields.
+func (s UpdateNotificationPreference) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.PushToken, validation.Length(0, 4096)),
+		validation.Field(&s.PhoneNumber, is.E164),
+		validation.Field(&s.Channels, validation.Required, validation.By(validateNotificationChannels)),
+	)
+}
+
+// validateNotificationChannels checks that every category and channel in the map is a known one.
+func validateNotificationChannels(value any) error {
+	channels, _ := value.(map[domain.NotificationCategory][]domain.NotificationChannel)
+
+	for category, list := range channels {
+		if !containsNotificationCategory(category) {
+			return fmt.Errorf("unknown category %q", category)
+		}
+		for _, channel := range list {
+			if !containsNotificationChannel(channel) {
+				return fmt.Errorf("unknown channel %q for category %q", channel, category)
+			}
+		}
+	}
+
+	if len(channels) != len(domain.NotificationCategories) {
+		return errors.New("every category must be present")
+	}
+
+	return nil
+}
+
+func containsNotificationCategory(category domain.NotificationCategory) bool {
+	for _, c := range domain.NotificationCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func containsNotificationChannel(channel domain.NotificationChannel) bool {
+	for _, c := range domain.NotificationChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
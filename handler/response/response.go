@@ -1,8 +1,45 @@
 package response
 
+import (
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/oklog/ulid/v2"
+)
+
 // CustomResponse struct defines the structure for custom API responses.
 // It includes the HTTP status code and a flexible data field for response content.
 type CustomResponse struct {
 	HTTPStatusCode int `json:"http_status_code,omitempty"`
 	ResponseData   any `json:"response_data,omitempty"`
 }
+
+// PaginatedResponse extends CustomResponse for list endpoints with the cursor
+// of the next page, empty on the last page, and the total number of items.
+type PaginatedResponse struct {
+	CustomResponse
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int64  `json:"total"`
+}
+
+// InboxResponse extends PaginatedResponse for the notification inbox with the number of
+// unread notifications in the whole inbox, whatever the filters of the page.
+type InboxResponse struct {
+	PaginatedResponse
+	Unread int64 `json:"unread"`
+}
+
+// NewPaginated builds a PaginatedResponse whose response data is the page items.
+func NewPaginated[T any](status int, page *domain.Page[T]) PaginatedResponse {
+	res := PaginatedResponse{
+		CustomResponse: CustomResponse{
+			HTTPStatusCode: status,
+			ResponseData:   page.Items,
+		},
+		Total: page.Total,
+	}
+
+	if page.NextCursor != (ulid.ULID{}) {
+		res.NextCursor = page.NextCursor.String()
+	}
+
+	return res
+}
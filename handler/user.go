@@ -8,6 +8,7 @@ import (
 	"github.com/j03hanafi/seternak-backend/handler/response"
 	"github.com/j03hanafi/seternak-backend/utils/consts"
 	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/j03hanafi/seternak-backend/utils/metrics"
 	"go.uber.org/zap"
 )
 
@@ -70,7 +71,9 @@ func (u *User) SignUp(c *fiber.Ctx) error {
 	}
 
 	// sign up user
-	if err := u.userService.SignUp(ctx, user); err != nil {
+	err := u.userService.SignUp(ctx, user)
+	metrics.AuthEvent(metrics.AuthSignUp, err)
+	if err != nil {
 		l.Info("Unable to sign up user",
 			zap.Error(err),
 		)
@@ -112,6 +115,7 @@ func (u *User) LogIn(c *fiber.Ctx) error {
 
 	// sign in user
 	if err := u.userService.LogIn(ctx, user); err != nil {
+		metrics.AuthEvent(metrics.AuthLogIn, err)
 		l.Info("Unable to sign in user",
 			zap.Error(err),
 		)
@@ -120,6 +124,7 @@ func (u *User) LogIn(c *fiber.Ctx) error {
 
 	// create token pair as strings
 	authToken, err := u.authService.NewPairFromUser(ctx, user, "")
+	metrics.AuthEvent(metrics.AuthLogIn, err)
 	if err != nil {
 		l.Info("Unable to create token pair for user",
 			zap.Error(err),
@@ -148,7 +153,9 @@ func (u *User) SignOut(c *fiber.Ctx) error {
 	user := c.Locals(consts.JWTUserContextKey).(*domain.User)
 
 	// sign out user
-	if err := u.userService.LogOut(ctx, user.UID); err != nil {
+	err := u.userService.LogOut(ctx, user.UID)
+	metrics.AuthEvent(metrics.AuthLogOut, err)
+	if err != nil {
 		l.Info("Unable to sign out user",
 			zap.Error(err),
 		)
@@ -174,6 +181,7 @@ func (u *User) Tokens(c *fiber.Ctx) error {
 	// get up-to-date user data
 	user, err := u.userService.Get(ctx, refreshToken.UID)
 	if err != nil {
+		metrics.AuthEvent(metrics.AuthTokenRefresh, err)
 		l.Error("Unable to get user",
 			zap.Error(err),
 		)
@@ -182,6 +190,7 @@ func (u *User) Tokens(c *fiber.Ctx) error {
 
 	// create token pair as strings
 	authToken, err := u.authService.NewPairFromUser(ctx, user, refreshToken.ID.String())
+	metrics.AuthEvent(metrics.AuthTokenRefresh, err)
 	if err != nil {
 		l.Info("Unable to create token pair for user",
 			zap.Error(err),
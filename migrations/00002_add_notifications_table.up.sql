@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id bytea NOT NULL PRIMARY KEY,
+    user_uid bytea NOT NULL REFERENCES users (uid) ON DELETE CASCADE,
+    category VARCHAR NOT NULL,
+    title VARCHAR NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_uid ON notifications (user_uid, id DESC);
+
+CREATE TABLE IF NOT EXISTS notification_preferences
+(
+    user_uid bytea NOT NULL PRIMARY KEY REFERENCES users (uid) ON DELETE CASCADE,
+    push_token VARCHAR NOT NULL DEFAULT '',
+    phone_number VARCHAR NOT NULL DEFAULT '',
+    channels JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+    );
\ No newline at end of file
@@ -0,0 +1,163 @@
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+	"os"
+	"strings"
+	"time"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Migrator runs the SQL migrations embedded in the binary against a Postgres database.
+// Every run holds a Postgres advisory lock, so instances starting together apply
+// migrations one at a time instead of racing each other.
+type Migrator struct {
+	migrate *migrate.Migrate
+	source  source.Driver
+}
+
+// Status describes a single embedded migration and whether it is applied
+type Status struct {
+	Version uint
+	Name    string
+	Applied bool
+	Dirty   bool
+}
+
+// New opens a dedicated connection to the database at dsn and prepares the embedded migrations.
+// lockTimeout bounds how long a run waits for another instance holding the migration lock.
+func New(dsn string, lockTimeout time.Duration, l *zap.Logger) (*Migrator, error) {
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading embedded migrations: %w", err)
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+
+	driver, err := pgx.WithInstance(db, &pgx.Config{})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("error initializing migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		_ = driver.Close()
+		return nil, fmt.Errorf("error initializing migrations: %w", err)
+	}
+
+	m.LockTimeout = lockTimeout
+	m.Log = &migrateLogger{l: l.Sugar()}
+
+	return &Migrator{
+		migrate: m,
+		source:  src,
+	}, nil
+}
+
+// Up applies n pending migrations, or all of them when n is not positive.
+// Having nothing to apply is not an error.
+func (m *Migrator) Up(n int) error {
+	var err error
+	if n > 0 {
+		err = m.migrate.Steps(n)
+	} else {
+		err = m.migrate.Up()
+	}
+	return ignoreNoChange(err)
+}
+
+// Down reverts the last n applied migrations, or all of them when n is not positive.
+// Having nothing to revert is not an error.
+func (m *Migrator) Down(n int) error {
+	var err error
+	if n > 0 {
+		err = m.migrate.Steps(-n)
+	} else {
+		err = m.migrate.Down()
+	}
+	return ignoreNoChange(err)
+}
+
+// Force sets the migration version without running any migration and clears the dirty flag.
+// It is meant to recover after a migration failed halfway and was fixed by hand.
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+// Status lists every embedded migration along with whether it is applied.
+func (m *Migrator) Status() ([]Status, error) {
+	current, dirty, err := m.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, err
+	}
+
+	var statuses []Status
+
+	version, err := m.source.First()
+	for err == nil {
+		r, name, readErr := m.source.ReadUp(version)
+		if readErr != nil {
+			return nil, readErr
+		}
+		_ = r.Close()
+
+		statuses = append(statuses, Status{
+			Version: version,
+			Name:    name,
+			Applied: version <= current,
+			Dirty:   dirty && version == current,
+		})
+
+		version, err = m.source.Next(version)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// Close closes the dedicated database connection.
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.migrate.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+// ignoreNoChange treats migrate.ErrNoChange as success
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// migrateLogger adapts a zap logger to migrate.Logger
+type migrateLogger struct {
+	l *zap.SugaredLogger
+}
+
+// Printf logs migrate progress messages
+func (m *migrateLogger) Printf(format string, v ...any) {
+	m.l.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+// Verbose enables migrate's verbose progress messages
+func (m *migrateLogger) Verbose() bool {
+	return true
+}
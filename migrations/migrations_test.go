@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"errors"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := src.First()
+	assert.NoError(t, err)
+
+	for err == nil {
+		up, _, upErr := src.ReadUp(version)
+		assert.NoError(t, upErr, "missing up migration for version %d", version)
+		down, _, downErr := src.ReadDown(version)
+		assert.NoError(t, downErr, "missing down migration for version %d", version)
+
+		if up != nil {
+			_ = up.Close()
+		}
+		if down != nil {
+			_ = down.Close()
+		}
+
+		version, err = src.Next(version)
+	}
+
+	assert.True(t, errors.Is(err, os.ErrNotExist), err)
+}
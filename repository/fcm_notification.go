@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/goccy/go-json"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/j03hanafi/seternak-backend/utils/tracing"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// fcmNotificationSender is a Firebase Cloud Messaging implementation of domain.NotificationSender
+type fcmNotificationSender struct {
+	client    *http.Client
+	url       string
+	serverKey string
+}
+
+// NewFCMNotification is a factory for initializing a push domain.NotificationSender
+func NewFCMNotification(client *http.Client, url, serverKey string) domain.NotificationSender {
+	return &fcmNotificationSender{
+		client:    client,
+		url:       url,
+		serverKey: serverKey,
+	}
+}
+
+// fcmMessage is the payload accepted by the FCM HTTP send endpoint
+type fcmMessage struct {
+	To           string            `json:"to"`
+	Notification map[string]string `json:"notification"`
+	Data         map[string]string `json:"data"`
+}
+
+// Channel returns domain.NotificationChannelPush
+func (s *fcmNotificationSender) Channel() domain.NotificationChannel {
+	return domain.NotificationChannelPush
+}
+
+// Send pushes the notification to the device token saved in the user's preference.
+// Returns an internal error if FCM cannot be reached or rejects the message.
+func (s *fcmNotificationSender) Send(ctx context.Context, u *domain.User, p *domain.NotificationPreference, n *domain.Notification) error {
+	l := logger.FromCtx(ctx)
+
+	if p.PushToken == "" {
+		l.Debug("User has no push token, skipping push notification", zap.String("uid", u.UID.String()))
+		return nil
+	}
+
+	body, err := json.Marshal(fcmMessage{
+		To: p.PushToken,
+		Notification: map[string]string{
+			"title": n.Title,
+			"body":  n.Body,
+		},
+		Data: map[string]string{
+			"id":       n.ID.String(),
+			"category": string(n.Category),
+		},
+	})
+	if err != nil {
+		return apperrors.NewInternal(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return apperrors.NewInternal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("key=%s", s.serverKey))
+
+	if err = doNotificationRequest(s.client, req); err != nil {
+		l.Error("Could not send push notification",
+			zap.String("uid", u.UID.String()),
+			zap.String("notificationID", n.ID.String()),
+			zap.Error(err),
+		)
+		return apperrors.NewInternal(err)
+	}
+
+	return nil
+}
+
+// doNotificationRequest sends req and treats any non-2xx response as an error
+func doNotificationRequest(client *http.Client, req *http.Request) (err error) {
+	ctx, span := tracing.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(req.Method), semconv.ServerAddress(req.URL.Hostname())),
+	)
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	// Propagate the trace to the provider as W3C traceparent
+	req = req.WithContext(ctx)
+	tracing.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(res.StatusCode))
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return nil
+}
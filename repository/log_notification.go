@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"go.uber.org/zap"
+)
+
+// logNotificationSender is a domain.NotificationSender that only logs notifications.
+// It stands in for channels that are not configured, e.g. when running locally.
+type logNotificationSender struct {
+	channel domain.NotificationChannel
+}
+
+// NewLogNotification is a factory for initializing a logging domain.NotificationSender
+func NewLogNotification(channel domain.NotificationChannel) domain.NotificationSender {
+	return &logNotificationSender{
+		channel: channel,
+	}
+}
+
+// Channel returns the channel this sender stands in for
+func (s *logNotificationSender) Channel() domain.NotificationChannel {
+	return s.channel
+}
+
+// Send logs the notification instead of delivering it. It never fails.
+func (s *logNotificationSender) Send(ctx context.Context, u *domain.User, _ *domain.NotificationPreference, n *domain.Notification) error {
+	l := logger.FromCtx(ctx)
+
+	l.Info("Notification delivered to log",
+		zap.String("channel", string(s.channel)),
+		zap.String("uid", u.UID.String()),
+		zap.String("notificationID", n.ID.String()),
+		zap.String("category", string(n.Category)),
+		zap.String("title", n.Title),
+	)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"github.com/goccy/go-json"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/oklog/ulid/v2"
+	"time"
+)
+
+// Notification defines the schema for the notifications table in the database.
+type Notification struct {
+	ID        ulid.ULID
+	UserUID   ulid.ULID
+	Category  string
+	Title     string
+	Body      string
+	ReadAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// FromNotification converts the domain.Notification struct to a Notification model.
+func (n *Notification) FromNotification(notification *domain.Notification) {
+	n.ID = notification.ID
+	n.UserUID = notification.UserUID
+	n.Category = string(notification.Category)
+	n.Title = notification.Title
+	n.Body = notification.Body
+	n.ReadAt = notification.ReadAt
+	n.CreatedAt = notification.CreatedAt
+}
+
+// ToNotification converts the Notification model to a domain.Notification struct.
+func (n *Notification) ToNotification() *domain.Notification {
+	return &domain.Notification{
+		ID:        n.ID,
+		UserUID:   n.UserUID,
+		Category:  domain.NotificationCategory(n.Category),
+		Title:     n.Title,
+		Body:      n.Body,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+// NotificationChannels is the JSONB representation of the enabled channels per category.
+type NotificationChannels map[domain.NotificationCategory][]domain.NotificationChannel
+
+// Value satisfies driver.Valuer by encoding the channels as JSON.
+func (c NotificationChannels) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan satisfies sql.Scanner by decoding the channels from JSON.
+func (c *NotificationChannels) Scan(value any) error {
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	case nil:
+		*c = NotificationChannels{}
+		return nil
+	default:
+		return errors.New("unsupported type for notification channels")
+	}
+
+	return json.Unmarshal(b, c)
+}
+
+// NotificationPreference defines the schema for the notification_preferences table in the database.
+type NotificationPreference struct {
+	UserUID     ulid.ULID `gorm:"primaryKey"`
+	PushToken   string
+	PhoneNumber string
+	Channels    NotificationChannels `gorm:"type:jsonb"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// FromNotificationPreference converts the domain.NotificationPreference struct to a NotificationPreference model.
+func (p *NotificationPreference) FromNotificationPreference(preference *domain.NotificationPreference) {
+	p.UserUID = preference.UserUID
+	p.PushToken = preference.PushToken
+	p.PhoneNumber = preference.PhoneNumber
+	p.Channels = preference.Channels
+}
+
+// ToNotificationPreference converts the NotificationPreference model to a domain.NotificationPreference struct.
+func (p *NotificationPreference) ToNotificationPreference() *domain.NotificationPreference {
+	return &domain.NotificationPreference{
+		UserUID:     p.UserUID,
+		PushToken:   p.PushToken,
+		PhoneNumber: p.PhoneNumber,
+		Channels:    p.Channels,
+	}
+}
@@ -13,6 +13,7 @@ type User struct {
 	Email     string
 	Password  string
 	Name      string
+	Admin     bool
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
@@ -24,6 +25,7 @@ func (u *User) FromUser(user *domain.User) {
 	u.Email = user.Email
 	u.Password = user.Password
 	u.Name = user.Name
+	u.Admin = user.Admin
 }
 
 // ToUser converts the User model to a domain.User struct.
@@ -33,5 +35,6 @@ func (u *User) ToUser() *domain.User {
 		Email:    u.Email,
 		Password: u.Password,
 		Name:     u.Name,
+		Admin:    u.Admin,
 	}
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"gorm.io/gorm"
+)
+
+// pgHealthRepository is data/repository implementation of domain.HealthRepository for Postgres
+type pgHealthRepository struct {
+	db *gorm.DB
+}
+
+// NewPGHealth is a factory for initializing a Postgres domain.HealthRepository
+func NewPGHealth(db *gorm.DB) domain.HealthRepository {
+	return &pgHealthRepository{
+		db: db,
+	}
+}
+
+// Name returns "postgres"
+func (p *pgHealthRepository) Name() string {
+	return "postgres"
+}
+
+// Ping checks the Postgres connection pool can reach the database.
+// Returns an error if the database is unreachable or ctx expires first.
+func (p *pgHealthRepository) Ping(ctx context.Context) error {
+	db, err := p.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.PingContext(ctx)
+}
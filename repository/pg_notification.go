@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/repository/model"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// pgNotificationRepository is data/repository implementation of domain.NotificationRepository
+type pgNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewPGNotification is a factory for initializing domain.NotificationRepository
+func NewPGNotification(db *gorm.DB) domain.NotificationRepository {
+	return &pgNotificationRepository{
+		db: db,
+	}
+}
+
+// Create inserts a new notification record into the Postgresql database.
+// Returns a general internal error if the operation fails.
+func (p *pgNotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	l := logger.FromCtx(ctx)
+
+	notification := new(model.Notification)
+	notification.FromNotification(n)
+
+	if err := conn(ctx, p.db).Create(notification).Error; err != nil {
+		l.Error("Could not create a notification", zap.Error(err))
+		return apperrors.NewInternal(err)
+	}
+
+	n.CreatedAt = notification.CreatedAt
+	return nil
+}
+
+// FindByUser retrieves a page of the notifications of a user, filtered and sorted as requested.
+// Returns the page or an internal error if the query fails.
+func (p *pgNotificationRepository) FindByUser(ctx context.Context, uid ulid.ULID, q *domain.ListQuery) (*domain.Page[*domain.Notification], error) {
+	l := logger.FromCtx(ctx)
+
+	var (
+		notifications []*model.Notification
+		total         int64
+	)
+
+	query := conn(ctx, p.db).
+		Model(&model.Notification{}).
+		Where("user_uid = ?", uid).
+		Scopes(Filter(q))
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		l.Error("Could not count notifications", zap.Error(err), zap.String("uid", uid.String()))
+		return nil, apperrors.NewInternal(err)
+	}
+
+	if err := query.Scopes(Paginate("notifications", "id", q)).Find(&notifications).Error; err != nil {
+		l.Error("Could not find notifications", zap.Error(err), zap.String("uid", uid.String()))
+		return nil, apperrors.NewInternal(err)
+	}
+
+	return NewPage(notifications, q, total,
+		func(n *model.Notification) ulid.ULID { return n.ID },
+		(*model.Notification).ToNotification,
+	), nil
+}
+
+// CountUnread counts the notifications of the user whose read time is not set.
+// Returns the count or an internal error if the query fails.
+func (p *pgNotificationRepository) CountUnread(ctx context.Context, uid ulid.ULID) (int64, error) {
+	l := logger.FromCtx(ctx)
+
+	var unread int64
+	err := conn(ctx, p.db).
+		Model(&model.Notification{}).
+		Where("user_uid = ? AND read_at IS NULL", uid).
+		Count(&unread).Error
+	if err != nil {
+		l.Error("Could not count unread notifications", zap.Error(err), zap.String("uid", uid.String()))
+		return 0, apperrors.NewInternal(err)
+	}
+
+	return unread, nil
+}
+
+// MarkRead sets the read time of a notification owned by the user.
+// Returns a not found error if no such notification exists, or an internal error if the update fails.
+func (p *pgNotificationRepository) MarkRead(ctx context.Context, uid, id ulid.ULID, readAt time.Time) error {
+	l := logger.FromCtx(ctx)
+
+	result := conn(ctx, p.db).
+		Model(&model.Notification{}).
+		Where("id = ? AND user_uid = ?", id, uid).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", readAt))
+	if err := result.Error; err != nil {
+		l.Error("Could not mark notification as read", zap.Error(err), zap.String("id", id.String()))
+		return apperrors.NewInternal(err)
+	}
+
+	if result.RowsAffected < 1 {
+		return apperrors.NewNotFound(gorm.ErrRecordNotFound, fmt.Sprintf("notification: %s", id.String()))
+	}
+
+	return nil
+}
+
+// MarkAllRead sets the read time of every unread notification of the user.
+// Returns an internal error if the update fails.
+func (p *pgNotificationRepository) MarkAllRead(ctx context.Context, uid ulid.ULID, readAt time.Time) error {
+	l := logger.FromCtx(ctx)
+
+	err := conn(ctx, p.db).
+		Model(&model.Notification{}).
+		Where("user_uid = ? AND read_at IS NULL", uid).
+		Update("read_at", readAt).Error
+	if err != nil {
+		l.Error("Could not mark notifications as read", zap.Error(err), zap.String("uid", uid.String()))
+		return apperrors.NewInternal(err)
+	}
+
+	return nil
+}
+
+// FindPreference retrieves the notification preference saved by the user.
+// Returns a not found error if the user has not saved one, or an internal error if the query fails.
+func (p *pgNotificationRepository) FindPreference(ctx context.Context, uid ulid.ULID) (*domain.NotificationPreference, error) {
+	l := logger.FromCtx(ctx)
+
+	preference := new(model.NotificationPreference)
+
+	err := conn(ctx, p.db).Where("user_uid = ?", uid).First(preference).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound(err, fmt.Sprintf("notification preference: %s", uid.String()))
+		}
+
+		l.Error("Could not find notification preference", zap.Error(err), zap.String("uid", uid.String()))
+		return nil, apperrors.NewInternal(err)
+	}
+
+	return preference.ToNotificationPreference(), nil
+}
+
+// SavePreference inserts the user's notification preference or replaces the existing one.
+// Returns an internal error if the operation fails.
+func (p *pgNotificationRepository) SavePreference(ctx context.Context, np *domain.NotificationPreference) error {
+	l := logger.FromCtx(ctx)
+
+	preference := new(model.NotificationPreference)
+	preference.FromNotificationPreference(np)
+
+	err := conn(ctx, p.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_uid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"push_token", "phone_number", "channels", "updated_at"}),
+	}).Create(preference).Error
+	if err != nil {
+		l.Error("Could not save notification preference", zap.Error(err), zap.String("uid", np.UserUID.String()))
+		return apperrors.NewInternal(err)
+	}
+
+	return nil
+}
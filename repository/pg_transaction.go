@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// pgTransactor is data/repository implementation of domain.Transactor for Postgres
+type pgTransactor struct {
+	db *gorm.DB
+}
+
+// NewPGTransactor is a factory for initializing a Postgres domain.Transactor
+func NewPGTransactor(db *gorm.DB) domain.Transactor {
+	return &pgTransactor{
+		db: db,
+	}
+}
+
+// WithinTransaction runs fn in a transaction, or in a savepoint of the transaction already in ctx.
+// A panic in fn rolls the transaction back and is propagated.
+// Returns the error of fn as is, or an internal error if the transaction cannot begin or commit.
+func (p *pgTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	l := logger.FromCtx(ctx)
+
+	var fnErr error
+	err := conn(ctx, p.db).Transaction(func(tx *gorm.DB) error {
+		fnErr = fn(context.WithValue(ctx, txKey{}, tx))
+		return fnErr
+	})
+	if err != nil && fnErr == nil {
+		l.Error("Could not complete a transaction", zap.Error(err))
+		return apperrors.NewInternal(err)
+	}
+
+	return err
+}
+
+// conn returns db, or the transaction in ctx if any, bound to ctx.
+// Repositories must query through it to take part in transactions.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
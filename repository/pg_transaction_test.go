@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/id"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func initPGTransactor(t *testing.T) (domain.Transactor, domain.UserRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+
+	return NewPGTransactor(db), NewPGUser(db), mock
+}
+
+func TestPGTransactor_WithinTransaction(t *testing.T) {
+	ctx := context.Background()
+	uid := id.New()
+
+	t.Run("Commits when fn succeeds", func(t *testing.T) {
+		transactor, users, mock := initPGTransactor(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			return users.UpdateAdmin(ctx, uid, true)
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rolls back and returns the error of fn", func(t *testing.T) {
+		transactor, users, mock := initPGTransactor(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "users"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			return users.UpdateAdmin(ctx, uid, true)
+		})
+
+		var appErr *apperrors.Error
+		if assert.ErrorAs(t, err, &appErr) {
+			assert.Equal(t, apperrors.NotFound, appErr.Type)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rolls back when fn panics", func(t *testing.T) {
+		transactor, users, mock := initPGTransactor(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				_ = users.UpdateAdmin(ctx, uid, true)
+				panic("boom")
+			})
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Nested calls roll back to a savepoint only", func(t *testing.T) {
+		transactor, users, mock := initPGTransactor(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`UPDATE "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		errInner := errors.New("inner")
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := users.UpdateAdmin(ctx, uid, true); err != nil {
+				return err
+			}
+
+			err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				_ = users.UpdateAdmin(ctx, uid, false)
+				return errInner
+			})
+			assert.ErrorIs(t, err, errInner)
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Returns an internal error when the transaction cannot begin", func(t *testing.T) {
+		transactor, _, mock := initPGTransactor(t)
+		mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+
+		called := false
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+		assert.False(t, called)
+		var appErr *apperrors.Error
+		if assert.ErrorAs(t, err, &appErr) {
+			assert.Equal(t, apperrors.Internal, appErr.Type)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -35,7 +35,7 @@ func (p *pgUserRepository) Create(ctx context.Context, u *domain.User) error {
 	user.FromUser(u)
 
 	// UID is generated by the database, so we omit it from the creation
-	err := p.db.WithContext(ctx).Create(user).Error
+	err := conn(ctx, p.db).Create(user).Error
 	if err != nil {
 		l.Error("Could not create a user", zap.Error(err))
 
@@ -55,7 +55,7 @@ func (p *pgUserRepository) FindByEmail(ctx context.Context, email string) (*doma
 
 	user := new(model.User)
 
-	err := p.db.WithContext(ctx).Where("email = ?", email).First(user).Error
+	err := conn(ctx, p.db).Where("email = ?", email).First(user).Error
 	if err != nil {
 		l.Error("Could not find a user", zap.Error(err), zap.String("email", email))
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -76,7 +76,7 @@ func (p *pgUserRepository) FindByID(ctx context.Context, uid ulid.ULID) (*domain
 
 	user := new(model.User)
 
-	err := p.db.WithContext(ctx).Where("uid = ?", uid).First(user).Error
+	err := conn(ctx, p.db).Where("uid = ?", uid).First(user).Error
 	if err != nil {
 		l.Error("Could not find a user", zap.Error(err), zap.String("uid", uid.String()))
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -88,3 +88,21 @@ func (p *pgUserRepository) FindByID(ctx context.Context, uid ulid.ULID) (*domain
 
 	return user.ToUser(), nil
 }
+
+// UpdateAdmin sets the admin flag of the user with the given unique identifier.
+// Returns a not found error if no such user exists, or an internal error if the update fails.
+func (p *pgUserRepository) UpdateAdmin(ctx context.Context, uid ulid.ULID, admin bool) error {
+	l := logger.FromCtx(ctx)
+
+	result := conn(ctx, p.db).Model(&model.User{}).Where("uid = ?", uid).Update("admin", admin)
+	if err := result.Error; err != nil {
+		l.Error("Could not update user admin flag", zap.Error(err), zap.String("uid", uid.String()))
+		return apperrors.NewInternal(err)
+	}
+
+	if result.RowsAffected < 1 {
+		return apperrors.NewNotFound(gorm.ErrRecordNotFound, fmt.Sprintf("uid: %s", uid.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisHealthRepository is a repository that implements domain.HealthRepository for Redis
+type redisHealthRepository struct {
+	redis *redis.Client
+}
+
+// NewRedisHealth is a factory for initializing a Redis domain.HealthRepository
+func NewRedisHealth(redis *redis.Client) domain.HealthRepository {
+	return &redisHealthRepository{
+		redis: redis,
+	}
+}
+
+// Name returns "redis"
+func (r *redisHealthRepository) Name() string {
+	return "redis"
+}
+
+// Ping sends a PING to Redis.
+// Returns an error if Redis is unreachable or ctx expires first.
+func (r *redisHealthRepository) Ping(ctx context.Context) error {
+	return r.redis.Ping(ctx).Err()
+}
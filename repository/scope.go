@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Filter is a gorm scope applying the filters of a domain.ListQuery.
+// Use it on both the count and the page query so totals match the filtered list.
+func Filter(q *domain.ListQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, f := range q.Filters {
+			column := clause.Column{Name: f.Column}
+
+			switch f.Operator {
+			case domain.FilterIsNull:
+				if isNull, _ := f.Value.(bool); isNull {
+					db = db.Where(clause.Expr{SQL: "? IS NULL", Vars: []any{column}})
+				} else {
+					db = db.Where(clause.Expr{SQL: "? IS NOT NULL", Vars: []any{column}})
+				}
+			default:
+				db = db.Where(clause.Eq{Column: column, Value: f.Value})
+			}
+		}
+		return db
+	}
+}
+
+// Paginate is a gorm scope applying keyset pagination of a domain.ListQuery over table,
+// whose ULID primary key is key. Rows are ordered by the sort column, then by key, and the
+// cursor is the key of the last row of the previous page. One extra row is fetched so
+// NewPage can tell whether another page exists.
+func Paginate(table, key string, q *domain.ListQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		sort := q.Sort
+		if sort == "" {
+			sort = key
+		}
+
+		op := ">"
+		if q.Desc {
+			op = "<"
+		}
+
+		if q.Cursor != (ulid.ULID{}) {
+			if sort == key {
+				db = db.Where(fmt.Sprintf("%s %s ?", quote(db, key), op), q.Cursor)
+			} else {
+				// Compare (sort, key) as a row so rows sharing a sort value are not skipped
+				db = db.Where(fmt.Sprintf("(%[1]s, %[2]s) %[3]s (SELECT %[1]s, %[2]s FROM %[4]s WHERE %[2]s = ?)",
+					quote(db, sort), quote(db, key), op, quote(db, table)), q.Cursor)
+			}
+		}
+
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: sort}, Desc: q.Desc})
+		if sort != key {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: key}, Desc: q.Desc})
+		}
+
+		return db.Limit(q.Limit + 1)
+	}
+}
+
+// NewPage converts the rows fetched with Paginate into a domain.Page, trimming the extra
+// row and setting the next cursor from the last returned item when another page exists.
+func NewPage[M any, T any](rows []M, q *domain.ListQuery, total int64, key func(M) ulid.ULID, convert func(M) T) *domain.Page[T] {
+	page := &domain.Page[T]{
+		Total: total,
+	}
+
+	if len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+		page.NextCursor = key(rows[len(rows)-1])
+	}
+
+	page.Items = make([]T, 0, len(rows))
+	for _, row := range rows {
+		page.Items = append(page.Items, convert(row))
+	}
+
+	return page
+}
+
+// quote quotes an identifier for the dialect of db
+func quote(db *gorm.DB, name string) string {
+	return db.Statement.Quote(name)
+}
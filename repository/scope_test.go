@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/repository/model"
+	"github.com/j03hanafi/seternak-backend/utils/id"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func initDryRunDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestPaginate(t *testing.T) {
+	db := initDryRunDB(t)
+
+	t.Run("first page sorted by key", func(t *testing.T) {
+		q := &domain.ListQuery{Limit: 20, Desc: true}
+
+		stmt := db.Model(&model.Notification{}).Scopes(Paginate("notifications", "id", q)).Find(&[]model.Notification{}).Statement
+
+		assert.Equal(t, `SELECT * FROM "notifications" ORDER BY "id" DESC LIMIT 21`, stmt.SQL.String())
+	})
+
+	t.Run("next page sorted by key", func(t *testing.T) {
+		q := &domain.ListQuery{Cursor: id.New(), Limit: 20}
+
+		stmt := db.Model(&model.Notification{}).Scopes(Paginate("notifications", "id", q)).Find(&[]model.Notification{}).Statement
+
+		assert.Equal(t, `SELECT * FROM "notifications" WHERE "id" > $1 ORDER BY "id" LIMIT 21`, stmt.SQL.String())
+		assert.Equal(t, []any{q.Cursor}, stmt.Vars)
+	})
+
+	t.Run("next page sorted by another column", func(t *testing.T) {
+		q := &domain.ListQuery{Cursor: id.New(), Limit: 5, Sort: "category", Desc: true}
+
+		stmt := db.Model(&model.Notification{}).Scopes(Paginate("notifications", "id", q)).Find(&[]model.Notification{}).Statement
+
+		assert.Equal(t, `SELECT * FROM "notifications" WHERE ("category", "id") < (SELECT "category", "id" FROM "notifications" WHERE "id" = $1) ORDER BY "category" DESC,"id" DESC LIMIT 6`, stmt.SQL.String())
+	})
+}
+
+func TestFilter(t *testing.T) {
+	db := initDryRunDB(t)
+
+	q := &domain.ListQuery{Filters: []domain.Filter{
+		{Column: "category", Operator: domain.FilterEqual, Value: "task"},
+		{Column: "read_at", Operator: domain.FilterIsNull, Value: false},
+	}}
+
+	stmt := db.Model(&model.Notification{}).Scopes(Filter(q)).Find(&[]model.Notification{}).Statement
+
+	assert.Equal(t, `SELECT * FROM "notifications" WHERE "category" = $1 AND "read_at" IS NOT NULL`, stmt.SQL.String())
+	assert.Equal(t, []any{"task"}, stmt.Vars)
+}
+
+func TestNewPage(t *testing.T) {
+	ids := []ulid.ULID{id.New(), id.New(), id.New()}
+	key := func(u ulid.ULID) ulid.ULID { return u }
+	convert := func(u ulid.ULID) string { return u.String() }
+
+	t.Run("more rows than limit", func(t *testing.T) {
+		page := NewPage(ids, &domain.ListQuery{Limit: 2}, 3, key, convert)
+
+		assert.Equal(t, []string{ids[0].String(), ids[1].String()}, page.Items)
+		assert.Equal(t, ids[1], page.NextCursor)
+		assert.EqualValues(t, 3, page.Total)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		page := NewPage(ids, &domain.ListQuery{Limit: 3}, 3, key, convert)
+
+		assert.Len(t, page.Items, 3)
+		assert.Zero(t, page.NextCursor)
+	})
+}
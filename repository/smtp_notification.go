@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"go.uber.org/zap"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// smtpNotificationSender is an email implementation of domain.NotificationSender
+type smtpNotificationSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// SMTPNotificationConfig holds the SMTP server settings used to send emails
+type SMTPNotificationConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPNotification is a factory for initializing an email domain.NotificationSender
+func NewSMTPNotification(c *SMTPNotificationConfig) domain.NotificationSender {
+	sender := &smtpNotificationSender{
+		addr: net.JoinHostPort(c.Host, c.Port),
+		from: c.From,
+	}
+
+	if c.Username != "" {
+		sender.auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+
+	return sender
+}
+
+// Channel returns domain.NotificationChannelEmail
+func (s *smtpNotificationSender) Channel() domain.NotificationChannel {
+	return domain.NotificationChannelEmail
+}
+
+// Send emails the notification to the user's email address, giving up once ctx is done.
+// Returns an internal error if the SMTP server cannot be reached in time or rejects the message.
+func (s *smtpNotificationSender) Send(ctx context.Context, u *domain.User, _ *domain.NotificationPreference, n *domain.Notification) error {
+	l := logger.FromCtx(ctx)
+
+	if u.Email == "" {
+		l.Debug("User has no email address, skipping email notification", zap.String("uid", u.UID.String()))
+		return nil
+	}
+
+	msg := strings.Join([]string{
+		fmt.Sprintf("From: %s", s.from),
+		fmt.Sprintf("To: %s", u.Email),
+		fmt.Sprintf("Subject: %s", n.Title),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"UTF-8\"",
+		"",
+		n.Body,
+	}, "\r\n")
+
+	if err := s.sendMail(ctx, u.Email, []byte(msg)); err != nil {
+		l.Error("Could not send email notification",
+			zap.String("uid", u.UID.String()),
+			zap.String("notificationID", n.ID.String()),
+			zap.Error(err),
+		)
+		return apperrors.NewInternal(err)
+	}
+
+	return nil
+}
+
+// sendMail works like smtp.SendMail, upgrading to TLS when the server supports it,
+// but aborts the exchange when ctx is done rather than waiting on the server forever.
+func (s *smtpNotificationSender) sendMail(ctx context.Context, to string, msg []byte) (err error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	// Closing the connection unblocks whatever SMTP command is waiting on the server
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer func() {
+		if !stop() && ctx.Err() != nil {
+			err = errors.Join(ctx.Err(), err)
+		}
+	}()
+
+	host, _, _ := net.SplitHostPort(s.addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if s.auth != nil {
+		if err = c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+
+	if err = c.Mail(s.from); err != nil {
+		return err
+	}
+	if err = c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(msg); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
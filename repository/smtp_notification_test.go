@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/utils/id"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSMTPNotificationSender_Send(t *testing.T) {
+	// A server accepting connections but never greeting the client
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	sender := NewSMTPNotification(&SMTPNotificationConfig{Host: host, Port: port, From: "no-reply@seternak.id"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = sender.Send(ctx,
+		&domain.User{UID: id.New(), Email: "farmer@seternak.id"},
+		&domain.NotificationPreference{},
+		&domain.Notification{ID: id.New(), Title: "Deworm goats"},
+	)
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/goccy/go-json"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// whatsAppNotificationSender is a WhatsApp Cloud API implementation of domain.NotificationSender
+type whatsAppNotificationSender struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewWhatsAppNotification is a factory for initializing a WhatsApp domain.NotificationSender.
+// baseURL is the Graph API base URL and phoneNumberID the sending business phone number.
+func NewWhatsAppNotification(client *http.Client, baseURL, phoneNumberID, token string) domain.NotificationSender {
+	return &whatsAppNotificationSender{
+		client: client,
+		url:    fmt.Sprintf("%s/%s/messages", baseURL, phoneNumberID),
+		token:  token,
+	}
+}
+
+// whatsAppMessage is the text message payload accepted by the WhatsApp Cloud API
+type whatsAppMessage struct {
+	MessagingProduct string            `json:"messaging_product"`
+	To               string            `json:"to"`
+	Type             string            `json:"type"`
+	Text             map[string]string `json:"text"`
+}
+
+// Channel returns domain.NotificationChannelWhatsApp
+func (s *whatsAppNotificationSender) Channel() domain.NotificationChannel {
+	return domain.NotificationChannelWhatsApp
+}
+
+// Send messages the notification to the phone number saved in the user's preference.
+// Returns an internal error if the API cannot be reached or rejects the message.
+func (s *whatsAppNotificationSender) Send(ctx context.Context, u *domain.User, p *domain.NotificationPreference, n *domain.Notification) error {
+	l := logger.FromCtx(ctx)
+
+	if p.PhoneNumber == "" {
+		l.Debug("User has no phone number, skipping WhatsApp notification", zap.String("uid", u.UID.String()))
+		return nil
+	}
+
+	body, err := json.Marshal(whatsAppMessage{
+		MessagingProduct: "whatsapp",
+		To:               p.PhoneNumber,
+		Type:             "text",
+		Text: map[string]string{
+			"body": fmt.Sprintf("*%s*\n%s", n.Title, n.Body),
+		},
+	})
+	if err != nil {
+		return apperrors.NewInternal(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return apperrors.NewInternal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.token))
+
+	if err = doNotificationRequest(s.client, req); err != nil {
+		l.Error("Could not send WhatsApp notification",
+			zap.String("uid", u.UID.String()),
+			zap.String("notificationID", n.ID.String()),
+			zap.Error(err),
+		)
+		return apperrors.NewInternal(err)
+	}
+
+	return nil
+}
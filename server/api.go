@@ -3,34 +3,50 @@ package server
 import (
 	"crypto/rsa"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/j03hanafi/seternak-backend/handler"
 	"github.com/j03hanafi/seternak-backend/server/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // api struct holds required handlers for api to function
 type api struct {
-	versionHandler *handler.Version
-	userHandler    *handler.User
+	versionHandler      *handler.Version
+	healthHandler       *handler.Health
+	userHandler         *handler.User
+	notificationHandler *handler.Notification
+	logHandler          *handler.Log
 }
 
 // apiConfig will hold handlers that will eventually be injected into this
 // api layer on api initialization
 type apiConfig struct {
-	app       *fiber.App
-	baseURL   string
-	version   *handler.Version
-	user      *handler.User
-	publicKey *rsa.PublicKey
-	secretKey string
+	app          *fiber.App
+	baseURL      string
+	version      *handler.Version
+	health       *handler.Health
+	user         *handler.User
+	notification *handler.Notification
+	log          *handler.Log
+	publicKey    *rsa.PublicKey
+	secretKey    string
 }
 
 // newAPI initializes the api with required injected handlers along with http routes
 func newAPI(c *apiConfig) {
 	h := &api{
-		versionHandler: c.version,
-		userHandler:    c.user,
+		versionHandler:      c.version,
+		healthHandler:       c.health,
+		userHandler:         c.user,
+		notificationHandler: c.notification,
+		logHandler:          c.log,
 	}
 
+	// Probes live outside the base url, where orchestrators expect them
+	c.app.Get("/healthz", h.healthHandler.Liveness)
+	c.app.Get("/readyz", h.healthHandler.Readiness)
+	c.app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+
 	// Create a group, or base url for all routes
 	g := c.app.Group(c.baseURL)
 
@@ -42,4 +58,15 @@ func newAPI(c *apiConfig) {
 	g.Post("/tokens", middleware.AuthRefresh(c.secretKey), h.userHandler.Tokens)
 
 	g.Post("/logout", middleware.AuthToken(c.publicKey), h.userHandler.SignOut)
+
+	n := g.Group("/notifications", middleware.AuthToken(c.publicKey))
+	n.Get("", h.notificationHandler.List)
+	n.Post("/read", h.notificationHandler.MarkAllRead)
+	n.Post("/:id/read", h.notificationHandler.MarkRead)
+	n.Get("/preferences", h.notificationHandler.GetPreference)
+	n.Put("/preferences", h.notificationHandler.UpdatePreference)
+
+	a := g.Group("/admin", middleware.AuthToken(c.publicKey), middleware.AdminOnly())
+	a.Get("/log-level", h.logHandler.GetLevel)
+	a.Put("/log-level", h.logHandler.SetLevel)
 }
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/consts"
+)
+
+// AdminOnly rejects users whose ID token does not grant admin rights.
+// It must come after AuthToken.
+func AdminOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals(consts.JWTUserContextKey).(*domain.User)
+		if !ok || !user.Admin {
+			return apperrors.NewForbidden(errors.New("user is not an admin"), "admin only")
+		}
+		return c.Next()
+	}
+}
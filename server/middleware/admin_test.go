@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/consts"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminOnly(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   *domain.User
+		status int
+	}{
+		{name: "admin", user: &domain.User{Admin: true}, status: fiber.StatusOK},
+		{name: "not admin", user: &domain.User{}, status: fiber.StatusForbidden},
+		{name: "no user", status: fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{
+				ErrorHandler: func(c *fiber.Ctx, err error) error {
+					return c.SendStatus(apperrors.Status(err))
+				},
+			})
+			app.Use(func(c *fiber.Ctx) error {
+				if tt.user != nil {
+					c.Locals(consts.JWTUserContextKey, tt.user)
+				}
+				return c.Next()
+			})
+			app.Get("/admin", AdminOnly(), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/admin", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = res.Body.Close()
+
+			assert.Equal(t, tt.status, res.StatusCode)
+		})
+	}
+}
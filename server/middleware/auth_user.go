@@ -9,7 +9,9 @@ import (
 	"github.com/j03hanafi/seternak-backend/domain/apperrors"
 	"github.com/j03hanafi/seternak-backend/utils"
 	"github.com/j03hanafi/seternak-backend/utils/consts"
+	"github.com/j03hanafi/seternak-backend/utils/metrics"
 	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
 )
 
 /*
@@ -33,6 +35,7 @@ func authTokenSuccessHandler() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claims := c.Locals(consts.JWTContextKey).(*jwt.Token).Claims.(*utils.IDTokenCustomClaims)
 		c.Locals(consts.JWTUserContextKey, claims.User)
+		withLoggerFields(c, zap.String("uid", claims.User.UID.String()))
 		return c.Next()
 	}
 }
@@ -74,6 +77,7 @@ func AuthRefreshSuccessHandler() fiber.Handler {
 		// parse claims.ID as a unique identifier
 		tokenID, err := ulid.Parse(claims.ID)
 		if err != nil {
+			metrics.AuthEvent(metrics.AuthTokenRefresh, err)
 			authErr := apperrors.NewBadRequest(err, consts.ErrBadRequestJWT)
 			return authErr
 		}
@@ -83,12 +87,16 @@ func AuthRefreshSuccessHandler() fiber.Handler {
 			UID: claims.UID,
 			SS:  tokenString,
 		})
+		withLoggerFields(c, zap.String("uid", claims.UID.String()))
 		return c.Next()
 	}
 }
 
 func AuthRefreshErrorHandler() fiber.ErrorHandler {
 	return func(c *fiber.Ctx, err error) error {
+		// Rejected refresh tokens never reach the handler, which counts the other refresh outcomes
+		metrics.AuthEvent(metrics.AuthTokenRefresh, err)
+
 		if err.Error() == consts.ErrBadRequestJWT {
 			authErr := apperrors.NewBadRequest(err, consts.ErrBadRequestJWT)
 			return authErr
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/utils/metrics"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthRefresh_Rejected(t *testing.T) {
+	failures := func() float64 {
+		return counterValue(t, "seternak_auth_events_total", map[string]string{"event": metrics.AuthTokenRefresh, "result": "failure"})
+	}
+	before := failures()
+
+	app := fiber.New()
+	app.Post("/tokens", AuthRefresh("refresh_token_secret"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for _, header := range []string{"", "Bearer not.a.token"} {
+		req := httptest.NewRequest(fiber.MethodPost, "/tokens", nil)
+		if header != "" {
+			req.Header.Set(fiber.HeaderAuthorization, header)
+		}
+
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = res.Body.Close()
+
+		assert.NotEqual(t, fiber.StatusOK, res.StatusCode)
+	}
+
+	assert.Equal(t, before+2, failures())
+}
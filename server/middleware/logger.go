@@ -6,11 +6,32 @@ import (
 	"go.uber.org/zap"
 )
 
+// Logger middleware attaches a child of l to the user context carrying the request ID, method,
+// route and client IP, so every logger.FromCtx call made for the request is correlated.
+// It must come after RequestID.
 func Logger(l *zap.Logger) fiber.Handler {
+	routes := new(routeMatcher)
+
 	return func(c *fiber.Ctx) error {
-		ctx := c.UserContext()
-		ctx = logger.WithCtx(ctx, l)
+		fields := []zap.Field{
+			zap.String("requestId", c.GetRespHeader(fiber.HeaderXRequestID)),
+			zap.String("method", c.Method()),
+			zap.String("ip", c.IP()),
+		}
+
+		// Routing has not reached the handler yet, so match the route beforehand
+		if route, ok := routes.match(c); ok {
+			fields = append(fields, zap.String("route", route))
+		}
+
+		ctx := logger.WithCtx(c.UserContext(), l.With(fields...))
 		c.SetUserContext(ctx)
 		return c.Next()
 	}
 }
+
+// withLoggerFields adds fields to the logger in the user context of c
+func withLoggerFields(c *fiber.Ctx, fields ...zap.Field) {
+	ctx := c.UserContext()
+	c.SetUserContext(logger.WithCtx(ctx, logger.FromCtx(ctx).With(fields...)))
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+
+	app := fiber.New()
+	app.Use(RequestID(), Logger(zap.New(core)))
+	app.Get("/animals", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/animals/:id", func(c *fiber.Ctx) error {
+		logger.FromCtx(c.UserContext()).Info("fetching animal")
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/animals/1", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+
+	if assert.Equal(t, 1, logs.Len()) {
+		fields := logs.All()[0].ContextMap()
+		assert.Equal(t, "/animals/:id", fields["route"])
+		assert.Equal(t, fiber.MethodGet, fields["method"])
+		assert.Equal(t, res.Header.Get(fiber.HeaderXRequestID), fields["requestId"])
+		assert.NotEmpty(t, fields["requestId"])
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/metrics"
+)
+
+// Metrics middleware records the count, latency and in-flight number of HTTP requests
+func Metrics() fiber.Handler {
+	routes := new(routeMatcher)
+
+	return func(c *fiber.Ctx) error {
+		// Fiber reuses the buffer behind c.Method, and metric labels outlive the request
+		done := metrics.HTTPRequestStarted(utils.CopyString(c.Method()))
+
+		err := c.Next()
+
+		status := responseStatus(c, err)
+
+		// Label by route template rather than path to keep cardinality bounded
+		route, ok := routes.match(c)
+		if !ok {
+			route = metrics.UnmatchedRoute
+		}
+
+		done(route, status)
+		return err
+	}
+}
+
+// responseStatus returns the status code of the response to c. The error handler
+// has not written the response yet when err is not nil, so derive it from err.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return apperrors.Status(err)
+}
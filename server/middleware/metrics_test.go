@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+// counterValue returns the value of the counter name with the given labels
+func counterValue(t *testing.T, name string, want map[string]string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			for k, v := range want {
+				if labels[k] != v {
+					continue metrics
+				}
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+
+	return 0
+}
+
+// requestCount returns seternak_http_requests_total for the given labels
+func requestCount(t *testing.T, method, route, status string) float64 {
+	t.Helper()
+	return counterValue(t, "seternak_http_requests_total", map[string]string{"method": method, "route": route, "status": status})
+}
+
+func TestMetrics(t *testing.T) {
+	app := fiber.New()
+	app.Use(Metrics())
+	app.Use(func(c *fiber.Ctx) error {
+		return c.Next()
+	})
+	app.Get("/animals/:id", func(c *fiber.Ctx) error {
+		return c.SendString(c.Params("id"))
+	})
+	app.Get("/missing/:id", func(c *fiber.Ctx) error {
+		return apperrors.NewNotFound(nil)
+	})
+
+	// A group whose middleware rejects every request before routing reaches the handlers
+	g := app.Group("/notifications", func(c *fiber.Ctx) error {
+		return apperrors.NewAuthorization(nil)
+	})
+	g.Get("/", func(c *fiber.Ctx) error { return nil })
+	g.Get("/preferences", func(c *fiber.Ctx) error { return nil })
+	g.Put("/preferences", func(c *fiber.Ctx) error { return nil })
+
+	requests := []struct{ method, path string }{
+		{fiber.MethodGet, "/animals/1"},
+		{fiber.MethodGet, "/animals/2"},
+		{fiber.MethodGet, "/missing/3"},
+		{fiber.MethodGet, "/nowhere/4"},
+		{fiber.MethodGet, "/nowhere/5"},
+		{fiber.MethodGet, "/notifications/preferences"},
+		{fiber.MethodPut, "/notifications/preferences"},
+	}
+	for _, r := range requests {
+		res, err := app.Test(httptest.NewRequest(r.method, r.path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = res.Body.Close()
+	}
+
+	assert.Equal(t, float64(2), requestCount(t, fiber.MethodGet, "/animals/:id", "200"))
+	assert.Equal(t, float64(1), requestCount(t, fiber.MethodGet, "/missing/:id", "404"))
+	assert.Equal(t, float64(2), requestCount(t, fiber.MethodGet, metrics.UnmatchedRoute, "404"))
+	assert.Zero(t, requestCount(t, fiber.MethodGet, "/nowhere/4", "404"))
+	assert.Equal(t, float64(1), requestCount(t, fiber.MethodGet, "/notifications/preferences", "401"))
+	assert.Equal(t, float64(1), requestCount(t, fiber.MethodPut, "/notifications/preferences", "401"))
+	assert.Zero(t, requestCount(t, fiber.MethodGet, "/notifications", "401"))
+}
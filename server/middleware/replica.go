@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/j03hanafi/seternak-backend/utils/replica"
+)
+
+// ReadYourWrites starts a read-your-writes session for each request, so reads following
+// a write within the request go to the primary rather than to a lagging read replica.
+func ReadYourWrites() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(replica.WithSession(c.UserContext()))
+		return c.Next()
+	}
+}
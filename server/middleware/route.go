@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// routeMatcher finds the route a request is sent to among the routes registered on the app.
+// Unlike c.Route, it does not depend on routing having reached the handler, so it also holds
+// for requests a middleware answers, such as a group middleware rejecting the credentials.
+type routeMatcher struct {
+	once   sync.Once
+	routes map[string][]compiledRoute
+}
+
+// compiledRoute is a route template along with the expression matching its paths
+type compiledRoute struct {
+	path    string
+	pattern *regexp.Regexp
+}
+
+// match returns the template of the first route registered for the method of c whose pattern
+// matches its path, the way Fiber routes requests. Returns false if no route matches.
+func (m *routeMatcher) match(c *fiber.Ctx) (string, bool) {
+	// Routes are registered after middlewares, so collect them on the first request
+	m.once.Do(func() {
+		m.routes = make(map[string][]compiledRoute)
+		for _, route := range c.App().GetRoutes(true) {
+			m.routes[route.Method] = append(m.routes[route.Method], compiledRoute{
+				path:    route.Path,
+				pattern: compileRoute(route.Path, c.App().Config()),
+			})
+		}
+	})
+
+	for _, route := range m.routes[c.Method()] {
+		if route.pattern.MatchString(c.Path()) {
+			return route.path, true
+		}
+	}
+	return "", false
+}
+
+// compileRoute turns a Fiber route template into a regular expression, once, rather than
+// parsing the template for every request. It handles the parameters this API registers:
+// named segments like :id, optional ones like :id? and the * and + wildcards.
+func compileRoute(path string, config fiber.Config) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !config.CaseSensitive {
+		expr.WriteString("(?i)")
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for _, segment := range segments {
+		switch {
+		case segment == "*":
+			expr.WriteString("(?:/.*)?")
+		case segment == "+":
+			expr.WriteString("/.+")
+		case strings.HasPrefix(segment, ":") && strings.HasSuffix(segment, "?"):
+			expr.WriteString("(?:/[^/]+)?")
+		case strings.HasPrefix(segment, ":"):
+			expr.WriteString("/[^/]+")
+		case segment == "":
+			// The root path, or a trailing slash
+		default:
+			expr.WriteString("/" + regexp.QuoteMeta(segment))
+		}
+	}
+
+	if config.StrictRouting && strings.HasSuffix(path, "/") {
+		expr.WriteString("/")
+	} else if !config.StrictRouting {
+		expr.WriteString("/?")
+	}
+	expr.WriteString("$")
+
+	return regexp.MustCompile(expr.String())
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompileRoute(t *testing.T) {
+	tests := []struct {
+		route string
+		path  string
+		want  bool
+	}{
+		{"/", "/", true},
+		{"/", "/animals", false},
+		{"/api/v1/notifications", "/api/v1/notifications", true},
+		{"/api/v1/notifications", "/API/v1/Notifications", true},
+		{"/api/v1/notifications", "/api/v1/notifications/preferences", false},
+		{"/api/v1/notifications/:id/read", "/api/v1/notifications/01HG/read", true},
+		{"/api/v1/notifications/:id/read", "/api/v1/notifications//read", false},
+		{"/animals/:id?", "/animals", true},
+		{"/animals/:id?", "/animals/1", true},
+		{"/animals/:id?", "/animals/1/2", false},
+		{"/files/*", "/files", true},
+		{"/files/*", "/files/a/b.txt", true},
+		{"/files/+", "/files", false},
+		{"/files/+", "/files/a/b.txt", true},
+		{"/v1.0/ping", "/v1x0/ping", false},
+	}
+
+	for _, tt := range tests {
+		got := compileRoute(tt.route, fiber.Config{}).MatchString(tt.path)
+		assert.Equal(t, tt.want, got, "%s against %s", tt.path, tt.route)
+		assert.Equal(t, tt.want, fiber.RoutePatternMatch(tt.path, tt.route), "fiber: %s against %s", tt.path, tt.route)
+	}
+}
+
+func TestRouteMatcher(t *testing.T) {
+	var (
+		matched string
+		ok      bool
+	)
+
+	routes := new(routeMatcher)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		matched, ok = routes.match(c)
+		return c.Next()
+	})
+
+	// A group middleware answering before routing reaches the handlers
+	g := app.Group("/notifications", func(c *fiber.Ctx) error {
+		return fiber.ErrUnauthorized
+	})
+	g.Get("/", func(c *fiber.Ctx) error { return nil })
+	g.Get("/preferences", func(c *fiber.Ctx) error { return nil })
+	g.Put("/preferences", func(c *fiber.Ctx) error { return nil })
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+		wantOK bool
+	}{
+		// Routes registered as / in a group carry a trailing slash
+		{fiber.MethodGet, "/notifications", "/notifications/", true},
+		{fiber.MethodGet, "/notifications/preferences/", "/notifications/preferences", true},
+		{fiber.MethodGet, "/notifications/preferences", "/notifications/preferences", true},
+		{fiber.MethodPut, "/notifications/preferences", "/notifications/preferences", true},
+		{fiber.MethodPost, "/notifications/preferences", "", false},
+		{fiber.MethodGet, "/nowhere", "", false},
+	}
+
+	for _, tt := range tests {
+		res, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = res.Body.Close()
+
+		assert.Equal(t, tt.wantOK, ok, "%s %s", tt.method, tt.path)
+		assert.Equal(t, tt.want, matched, "%s %s", tt.method, tt.path)
+	}
+}
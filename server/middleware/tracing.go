@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/j03hanafi/seternak-backend/utils/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing middleware continues the trace of an incoming W3C traceparent header, or starts
+// a new one, and passes the request span down through the user context.
+func Tracing() fiber.Handler {
+	routes := new(routeMatcher)
+
+	return func(c *fiber.Ctx) error {
+		ctx := tracing.Extract(c.UserContext(), &requestHeaderCarrier{c: c})
+
+		// Fiber reuses the buffers behind request values, and spans outlive the request
+		method := utils.CopyString(c.Method())
+
+		ctx, span := tracing.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(method),
+				semconv.URLPath(utils.CopyString(c.Path())),
+				semconv.ClientAddress(c.IP()),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		status := responseStatus(c, err)
+
+		// Name the span after the route template rather than the path to group requests.
+		// Requests matching no route keep the method alone as name, with no http.route.
+		if route, ok := routes.match(c); ok {
+			span.SetName(method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		span.SetAttributes(
+			semconv.HTTPResponseStatusCode(status),
+			attribute.String("http.request_id", utils.CopyString(c.GetRespHeader(fiber.HeaderXRequestID))),
+		)
+
+		// Client errors are the caller's fault, only server errors fail the span
+		if status >= fiber.StatusInternalServerError {
+			if err != nil {
+				tracing.RecordError(span, err)
+			} else {
+				span.SetStatus(codes.Error, utils.StatusMessage(status))
+			}
+		}
+
+		return err
+	}
+}
+
+// requestHeaderCarrier adapts the request headers of a fiber.Ctx to propagation.TextMapCarrier
+type requestHeaderCarrier struct {
+	c *fiber.Ctx
+}
+
+// Get returns the value of the request header key
+func (r *requestHeaderCarrier) Get(key string) string {
+	return r.c.Get(key)
+}
+
+// Set sets the request header key to value
+func (r *requestHeaderCarrier) Set(key, value string) {
+	r.c.Request().Header.Set(key, value)
+}
+
+// Keys lists the request header keys
+func (r *requestHeaderCarrier) Keys() []string {
+	var keys []string
+	r.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
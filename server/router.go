@@ -1,27 +1,88 @@
 package server
 
 import (
+	"context"
 	"github.com/gofiber/contrib/fiberzap/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	configuration "github.com/j03hanafi/seternak-backend/config"
+	"github.com/j03hanafi/seternak-backend/domain"
 	"github.com/j03hanafi/seternak-backend/handler"
 	"github.com/j03hanafi/seternak-backend/repository"
 	"github.com/j03hanafi/seternak-backend/server/middleware"
 	"github.com/j03hanafi/seternak-backend/service"
-	"github.com/spf13/viper"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/j03hanafi/seternak-backend/utils/metrics"
+	"github.com/j03hanafi/seternak-backend/utils/tracing"
+	"go.uber.org/zap"
+	"net/http"
 )
 
+// Server wraps the Fiber application along with the hooks used for a graceful shutdown
+type Server struct {
+	*fiber.App
+	health       *handler.Health
+	notification domain.NotificationService
+	config       *configuration.Config
+}
+
+// Drain makes the readiness endpoint fail so load balancers stop routing new
+// traffic to this instance, ahead of shutting the Fiber application down
+func (s *Server) Drain() {
+	s.health.Drain()
+}
+
+// Settings returns the validated settings the server was configured with
+func (s *Server) Settings() *configuration.Settings {
+	return s.config.GetSettings()
+}
+
+// Close waits for pending notification deliveries, then closes the connections to every
+// dependency, to be used after the Fiber application is shut down
+func (s *Server) Close() error {
+	// Every delivery is bounded by the send timeout, so waiting longer is pointless
+	ctx, cancel := context.WithTimeout(context.Background(), s.Settings().Notify.SendTimeout)
+	defer cancel()
+
+	if err := s.notification.Wait(ctx); err != nil {
+		logger.Get().Warn("Notification deliveries still pending at shutdown", zap.Error(err))
+	}
+
+	return s.config.Close()
+}
+
 // New initializes and returns a new Fiber application with configured middleware.
-// Returns a pointer to the fiber.App instance.
-func New() (*fiber.App, func() error) {
+// Returns a pointer to the Server wrapping the fiber.App instance.
+func New() *Server {
 	config := configuration.New()
+	settings := config.GetSettings()
+	l := config.GetLogger()
+
+	// Expose database and Redis pool stats
+	if err := metrics.RegisterDB(config.GetDB()); err != nil {
+		l.Fatal("Error registering database metrics", zap.Error(err))
+	}
+	if err := metrics.RegisterRedis(config.GetRedis()); err != nil {
+		l.Fatal("Error registering Redis metrics", zap.Error(err))
+	}
+
+	// Trace database statements and Redis commands under the request span
+	if err := tracing.RegisterDB(config.GetDB()); err != nil {
+		l.Fatal("Error registering database tracing", zap.Error(err))
+	}
+	if err := tracing.RegisterRedis(config.GetRedis()); err != nil {
+		l.Fatal("Error registering Redis tracing", zap.Error(err))
+	}
 
 	/*
 		Repository initialization
 	*/
 	userRepository := repository.NewPGUser(config.GetDB())
 	authRepository := repository.NewRedisAuth(config.GetRedis())
+	notificationRepository := repository.NewPGNotification(config.GetDB())
+	pgHealthRepository := repository.NewPGHealth(config.GetDB())
+	redisHealthRepository := repository.NewRedisHealth(config.GetRedis())
+	transactor := repository.NewPGTransactor(config.GetDB())
 
 	/*
 		Service initialization
@@ -29,35 +90,100 @@ func New() (*fiber.App, func() error) {
 	userService := service.NewUser(&service.UserServiceConfig{
 		UserRepository: userRepository,
 		AuthRepository: authRepository,
+		Transactor:     transactor,
 	})
 	authService := service.NewAuth(&service.AuthServiceConfig{
 		AuthRepository:             authRepository,
 		PrivateKey:                 config.GetPrivateKey(),
-		RefreshTokenSecret:         viper.GetString("REFRESH_TOKEN_SECRET"),
-		IDTokenExpirationSecs:      viper.GetInt64("ID_TOKEN_EXP"),
-		RefreshTokenExpirationSecs: viper.GetInt64("REFRESH_TOKEN_EXP"),
+		RefreshTokenSecret:         settings.Auth.RefreshTokenSecret,
+		IDTokenExpirationSecs:      settings.Auth.IDTokenExp,
+		RefreshTokenExpirationSecs: settings.Auth.RefreshTokenExp,
+	})
+	notificationService := service.NewNotification(&service.NotificationServiceConfig{
+		NotificationRepository: notificationRepository,
+		UserRepository:         userRepository,
+		Senders:                notificationSenders(settings.Notify),
+		SendTimeout:            settings.Notify.SendTimeout,
+	})
+	healthService := service.NewHealth(&service.HealthServiceConfig{
+		HealthRepositories: []domain.HealthRepository{pgHealthRepository, redisHealthRepository},
+		Timeout:            settings.App.HealthCheckTimeout,
 	})
 
 	// Fiber instance
 	app := fiber.New(*config.GetFiberConfig())
 	app.Use(fiberzap.New(*config.GetFiberzapConfig()))
+	app.Use(middleware.Metrics()) // before recover, so panics are counted as 500s
+	app.Use(middleware.Tracing()) // before logger, so request loggers carry the trace ID
 	app.Use(recover.New(*config.GetRecoverConfig()))
-	app.Use(middleware.Logger(config.GetLogger()), middleware.Compression(), middleware.RequestID())
+	app.Use(middleware.RequestID(), middleware.Logger(config.GetLogger()), middleware.Compression())
+	app.Use(middleware.ReadYourWrites())
 
 	/*
 		API initialization
 	*/
+	healthHandler := handler.NewHealth(&handler.HealthHandlerConfig{
+		HealthService: healthService,
+	})
 	newAPI(&apiConfig{
 		app:     app,
-		baseURL: viper.GetString("API_URL"),
+		baseURL: settings.App.APIURL,
 		version: handler.NewVersion(),
+		health:  healthHandler,
 		user: handler.NewUser(&handler.UserHandlerConfig{
 			UserService: userService,
 			AuthService: authService,
 		}),
+		notification: handler.NewNotification(&handler.NotificationHandlerConfig{
+			NotificationService: notificationService,
+		}),
+		log: handler.NewLog(&handler.LogHandlerConfig{
+			Level: logger.Level(),
+		}),
 		publicKey: config.GetPublicKey(),
-		secretKey: viper.GetString("REFRESH_TOKEN_SECRET"),
+		secretKey: settings.Auth.RefreshTokenSecret,
 	})
 
-	return app, config.Close
+	return &Server{
+		App:          app,
+		health:       healthHandler,
+		notification: notificationService,
+		config:       config,
+	}
+}
+
+// notificationSenders builds a sender for every delivery channel besides in-app, which is
+// served from the inbox itself. Channels without credentials configured fall back to a
+// sender that only logs, so notifications can be exercised locally.
+func notificationSenders(settings configuration.NotifySettings) []domain.NotificationSender {
+	client := &http.Client{Timeout: settings.HTTPTimeout}
+
+	var senders []domain.NotificationSender
+
+	if settings.SMTPHost != "" {
+		senders = append(senders, repository.NewSMTPNotification(&repository.SMTPNotificationConfig{
+			Host:     settings.SMTPHost,
+			Port:     settings.SMTPPort,
+			Username: settings.SMTPUser,
+			Password: settings.SMTPPass,
+			From:     settings.SMTPFrom,
+		}))
+	} else {
+		senders = append(senders, repository.NewLogNotification(domain.NotificationChannelEmail))
+	}
+
+	if settings.FCMServerKey != "" {
+		senders = append(senders, repository.NewFCMNotification(client, settings.FCMURL, settings.FCMServerKey))
+	} else {
+		senders = append(senders, repository.NewLogNotification(domain.NotificationChannelPush))
+	}
+
+	if settings.WhatsAppToken != "" {
+		senders = append(senders, repository.NewWhatsAppNotification(client,
+			settings.WhatsAppURL, settings.WhatsAppPhoneNumberID, settings.WhatsAppToken))
+	} else {
+		senders = append(senders, repository.NewLogNotification(domain.NotificationChannelWhatsApp))
+	}
+
+	return senders
 }
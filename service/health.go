@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// healthService acts as a struct for injecting the dependencies to check
+// for use in service methods
+type healthService struct {
+	healthRepositories []domain.HealthRepository
+	timeout            time.Duration
+}
+
+// HealthServiceConfig will hold the dependencies to check that will eventually be injected
+// into this service layer
+type HealthServiceConfig struct {
+	HealthRepositories []domain.HealthRepository
+	Timeout            time.Duration
+}
+
+// NewHealth is a factory function for
+// initializing a healthService with the dependencies it checks
+func NewHealth(c *HealthServiceConfig) domain.HealthService {
+	service := &healthService{
+		timeout: 2 * time.Second,
+	}
+
+	if c.HealthRepositories != nil {
+		service.healthRepositories = c.HealthRepositories
+	}
+
+	if c.Timeout > 0 {
+		service.timeout = c.Timeout
+	}
+
+	return service
+}
+
+// Check pings every dependency concurrently so a slow one does not delay the others,
+// each ping bounded by the service timeout.
+// Returns the result per dependency, in registration order, and whether all are up.
+func (s *healthService) Check(ctx context.Context) ([]*domain.DependencyHealth, bool) {
+	l := logger.FromCtx(ctx)
+
+	results := make([]*domain.DependencyHealth, len(s.healthRepositories))
+
+	var wg sync.WaitGroup
+	for i, repository := range s.healthRepositories {
+		wg.Add(1)
+		go func(i int, repository domain.HealthRepository) {
+			defer wg.Done()
+
+			pingCtx, cancel := context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := repository.Ping(pingCtx)
+			latency := time.Since(start)
+
+			result := &domain.DependencyHealth{
+				Name:      repository.Name(),
+				Status:    domain.HealthStatusUp,
+				Latency:   latency,
+				LatencyMs: float64(latency.Microseconds()) / 1000,
+			}
+			if err != nil {
+				l.Warn("dependency health check failed",
+					zap.String("dependency", repository.Name()),
+					zap.Error(err),
+				)
+				result.Status = domain.HealthStatusDown
+				result.Error = err.Error()
+			}
+
+			results[i] = result
+		}(i, repository)
+	}
+	wg.Wait()
+
+	healthy := true
+	for _, result := range results {
+		if result.Status != domain.HealthStatusUp {
+			healthy = false
+		}
+	}
+
+	return results, healthy
+}
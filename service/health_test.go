@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestHealthService_Check(t *testing.T) {
+	ctx := context.Background()
+
+	up := new(mocks.MockHealthRepository)
+	up.On("Name").Return("postgres")
+	up.On("Ping", mock.Anything).Return(nil)
+
+	// hangs until the per-dependency timeout cancels its context
+	hanging := new(mocks.MockHealthRepository)
+	hanging.On("Name").Return("redis")
+	hanging.On("Ping", mock.Anything).Return(context.DeadlineExceeded).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	})
+
+	t.Run("all dependencies up", func(t *testing.T) {
+		s := NewHealth(&HealthServiceConfig{
+			HealthRepositories: []domain.HealthRepository{up},
+		})
+
+		results, healthy := s.Check(ctx)
+
+		assert.True(t, healthy)
+		assert.Len(t, results, 1)
+		assert.Equal(t, domain.HealthStatusUp, results[0].Status)
+	})
+
+	t.Run("dependency exceeding the timeout is down", func(t *testing.T) {
+		s := NewHealth(&HealthServiceConfig{
+			HealthRepositories: []domain.HealthRepository{up, hanging},
+			Timeout:            20 * time.Millisecond,
+		})
+
+		results, healthy := s.Check(ctx)
+
+		assert.False(t, healthy)
+		assert.Equal(t, "postgres", results[0].Name)
+		assert.Equal(t, domain.HealthStatusUp, results[0].Status)
+		assert.Equal(t, "redis", results[1].Name)
+		assert.Equal(t, domain.HealthStatusDown, results[1].Status)
+		assert.NotEmpty(t, results[1].Error)
+	})
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/utils/id"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// notificationService acts as a struct for injecting an implementation of repositories
+// and delivery channels for use in service methods
+type notificationService struct {
+	notificationRepository domain.NotificationRepository
+	userRepository         domain.UserRepository
+	senders                map[domain.NotificationChannel]domain.NotificationSender
+	sendTimeout            time.Duration
+	deliveries             sync.WaitGroup
+}
+
+// NotificationServiceConfig will hold repositories and delivery channels that will
+// eventually be injected into this service layer
+type NotificationServiceConfig struct {
+	NotificationRepository domain.NotificationRepository
+	UserRepository         domain.UserRepository
+	Senders                []domain.NotificationSender
+	SendTimeout            time.Duration // no timeout when zero
+}
+
+// NewNotification is a factory function for
+// initializing a notificationService with its repository layer dependencies
+func NewNotification(c *NotificationServiceConfig) domain.NotificationService {
+	service := new(notificationService)
+
+	if c.NotificationRepository != nil {
+		service.notificationRepository = c.NotificationRepository
+	}
+
+	if c.UserRepository != nil {
+		service.userRepository = c.UserRepository
+	}
+
+	service.senders = make(map[domain.NotificationChannel]domain.NotificationSender, len(c.Senders))
+	for _, sender := range c.Senders {
+		service.senders[sender.Channel()] = sender
+	}
+
+	service.sendTimeout = c.SendTimeout
+
+	return service
+}
+
+// Notify stores the notification in the recipient's inbox if they enabled in-app notifications
+// for its category, then delivers it through every other channel enabled in their preference.
+// Deliveries run in the background, each bounded by the send timeout, so slow providers do not
+// hold up the caller; their failures are logged only.
+// Returns an error if the notification cannot be stored.
+func (s *notificationService) Notify(ctx context.Context, n *domain.Notification) error {
+	l := logger.FromCtx(ctx)
+
+	n.ID = id.New()
+	n.ReadAt = nil
+
+	preference, err := s.GetPreference(ctx, n.UserUID)
+	if err != nil {
+		// Keep the notification in the inbox rather than risk delivering it through disabled channels
+		l.Error("error fetching notification preference",
+			zap.Error(err),
+			zap.String("uid", n.UserUID.String()),
+		)
+		return s.notificationRepository.Create(ctx, n)
+	}
+
+	if preference.Enabled(n.Category, domain.NotificationChannelInApp) {
+		if err = s.notificationRepository.Create(ctx, n); err != nil {
+			return err
+		}
+	}
+
+	var senders []domain.NotificationSender
+	for channel, sender := range s.senders {
+		if channel != domain.NotificationChannelInApp && preference.Enabled(n.Category, channel) {
+			senders = append(senders, sender)
+		}
+	}
+	if len(senders) == 0 {
+		return nil
+	}
+
+	user, err := s.userRepository.FindByID(ctx, n.UserUID)
+	if err != nil {
+		l.Error("error fetching notification recipient",
+			zap.Error(err),
+			zap.String("uid", n.UserUID.String()),
+		)
+		return nil
+	}
+
+	// Deliveries outlive the caller, whose context may be canceled as soon as Notify returns
+	ctx = context.WithoutCancel(ctx)
+	for _, sender := range senders {
+		s.deliveries.Add(1)
+		go func(sender domain.NotificationSender) {
+			defer s.deliveries.Done()
+			s.deliver(ctx, sender, user, preference, n)
+		}(sender)
+	}
+
+	return nil
+}
+
+// deliver sends the notification through a single channel within the send timeout, logging any failure
+func (s *notificationService) deliver(ctx context.Context, sender domain.NotificationSender, u *domain.User, p *domain.NotificationPreference, n *domain.Notification) {
+	l := logger.FromCtx(ctx)
+
+	if s.sendTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.sendTimeout)
+		defer cancel()
+	}
+
+	if err := sender.Send(ctx, u, p, n); err != nil {
+		l.Error("error delivering notification",
+			zap.Error(err),
+			zap.String("channel", string(sender.Channel())),
+			zap.String("notificationID", n.ID.String()),
+		)
+	}
+}
+
+// Wait blocks until the deliveries started by Notify are done, or ctx expires.
+// Returns the error of ctx if deliveries are still pending.
+func (s *notificationService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.deliveries.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// List retrieves a page of the notifications in the user's inbox.
+// Returns the page or an error if the retrieval fails.
+func (s *notificationService) List(ctx context.Context, uid ulid.ULID, q *domain.ListQuery) (*domain.Page[*domain.Notification], error) {
+	return s.notificationRepository.FindByUser(ctx, uid, q)
+}
+
+// CountUnread counts the unread notifications in the user's inbox.
+// Returns the count or an error if the count fails.
+func (s *notificationService) CountUnread(ctx context.Context, uid ulid.ULID) (int64, error) {
+	return s.notificationRepository.CountUnread(ctx, uid)
+}
+
+// MarkRead marks a single notification in the user's inbox as read.
+// Returns a not found error if the user has no such notification.
+func (s *notificationService) MarkRead(ctx context.Context, uid, id ulid.ULID) error {
+	return s.notificationRepository.MarkRead(ctx, uid, id, time.Now())
+}
+
+// MarkAllRead marks every unread notification in the user's inbox as read.
+// Returns an error if the update fails.
+func (s *notificationService) MarkAllRead(ctx context.Context, uid ulid.ULID) error {
+	return s.notificationRepository.MarkAllRead(ctx, uid, time.Now())
+}
+
+// GetPreference retrieves the user's notification preference, falling back to
+// domain.DefaultNotificationPreference if the user has not saved one.
+// Returns the preference or an error if the retrieval fails.
+func (s *notificationService) GetPreference(ctx context.Context, uid ulid.ULID) (*domain.NotificationPreference, error) {
+	preference, err := s.notificationRepository.FindPreference(ctx, uid)
+	if err != nil {
+		var appErr *apperrors.Error
+		if errors.As(err, &appErr) && appErr.Type == apperrors.NotFound {
+			return domain.DefaultNotificationPreference(uid), nil
+		}
+		return nil, err
+	}
+
+	return preference, nil
+}
+
+// UpdatePreference replaces the user's notification preference.
+// Returns an error if the update fails.
+func (s *notificationService) UpdatePreference(ctx context.Context, p *domain.NotificationPreference) error {
+	return s.notificationRepository.SavePreference(ctx, p)
+}
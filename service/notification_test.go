@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/apperrors"
+	"github.com/j03hanafi/seternak-backend/domain/mocks"
+	"github.com/j03hanafi/seternak-backend/utils/id"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestNotificationService_Notify(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{UID: id.New(), Email: "farmer@seternak.id", Name: "Farmer"}
+
+	newSender := func(channel domain.NotificationChannel) *mocks.MockNotificationSender {
+		sender := new(mocks.MockNotificationSender)
+		sender.On("Channel").Return(channel)
+		return sender
+	}
+
+	t.Run("delivers through enabled channels only", func(t *testing.T) {
+		notificationRepository := new(mocks.MockNotificationRepository)
+		userRepository := new(mocks.MockUserRepository)
+		email := newSender(domain.NotificationChannelEmail)
+		push := newSender(domain.NotificationChannelPush)
+
+		preference := &domain.NotificationPreference{
+			UserUID: user.UID,
+			Channels: map[domain.NotificationCategory][]domain.NotificationChannel{
+				domain.NotificationCategoryTask: {domain.NotificationChannelInApp, domain.NotificationChannelPush},
+			},
+		}
+
+		notificationRepository.On("Create", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil)
+		notificationRepository.On("FindPreference", ctx, user.UID).Return(preference, nil)
+		userRepository.On("FindByID", ctx, user.UID).Return(user, nil)
+		push.On("Send", mock.Anything, user, preference, mock.AnythingOfType("*domain.Notification")).Return(nil)
+
+		s := NewNotification(&NotificationServiceConfig{
+			NotificationRepository: notificationRepository,
+			UserRepository:         userRepository,
+			Senders:                []domain.NotificationSender{email, push},
+		})
+
+		n := &domain.Notification{UserUID: user.UID, Category: domain.NotificationCategoryTask, Title: "Deworm goats"}
+		err := s.Notify(ctx, n)
+
+		assert.NoError(t, err)
+		assert.NoError(t, s.Wait(ctx))
+		assert.NotZero(t, n.ID)
+		push.AssertExpectations(t)
+		email.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("falls back to default preference and ignores delivery errors", func(t *testing.T) {
+		notificationRepository := new(mocks.MockNotificationRepository)
+		userRepository := new(mocks.MockUserRepository)
+		email := newSender(domain.NotificationChannelEmail)
+
+		notificationRepository.On("Create", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil)
+		notificationRepository.On("FindPreference", ctx, user.UID).Return(nil, apperrors.NewNotFound(errors.New("not found")))
+		userRepository.On("FindByID", ctx, user.UID).Return(user, nil)
+		email.On("Send", mock.Anything, user, mock.AnythingOfType("*domain.NotificationPreference"), mock.AnythingOfType("*domain.Notification")).
+			Return(errors.New("smtp unavailable"))
+
+		s := NewNotification(&NotificationServiceConfig{
+			NotificationRepository: notificationRepository,
+			UserRepository:         userRepository,
+			Senders:                []domain.NotificationSender{email},
+		})
+
+		err := s.Notify(ctx, &domain.Notification{UserUID: user.UID, Category: domain.NotificationCategorySystem, Title: "Welcome"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, s.Wait(ctx))
+		email.AssertExpectations(t)
+	})
+
+	t.Run("fails when the notification cannot be stored", func(t *testing.T) {
+		notificationRepository := new(mocks.MockNotificationRepository)
+		storeErr := apperrors.NewInternal(errors.New("db down"))
+
+		notificationRepository.On("FindPreference", ctx, user.UID).Return(nil, apperrors.NewNotFound(errors.New("not found")))
+		notificationRepository.On("Create", ctx, mock.AnythingOfType("*domain.Notification")).Return(storeErr)
+
+		s := NewNotification(&NotificationServiceConfig{
+			NotificationRepository: notificationRepository,
+		})
+
+		err := s.Notify(ctx, &domain.Notification{UserUID: user.UID, Category: domain.NotificationCategorySystem})
+
+		assert.ErrorIs(t, err, storeErr)
+	})
+
+	t.Run("skips the inbox when in-app is disabled", func(t *testing.T) {
+		notificationRepository := new(mocks.MockNotificationRepository)
+		userRepository := new(mocks.MockUserRepository)
+		email := newSender(domain.NotificationChannelEmail)
+
+		preference := &domain.NotificationPreference{
+			UserUID: user.UID,
+			Channels: map[domain.NotificationCategory][]domain.NotificationChannel{
+				domain.NotificationCategoryTask: {domain.NotificationChannelEmail},
+			},
+		}
+
+		notificationRepository.On("FindPreference", ctx, user.UID).Return(preference, nil)
+		userRepository.On("FindByID", ctx, user.UID).Return(user, nil)
+		email.On("Send", mock.Anything, user, preference, mock.AnythingOfType("*domain.Notification")).Return(nil)
+
+		s := NewNotification(&NotificationServiceConfig{
+			NotificationRepository: notificationRepository,
+			UserRepository:         userRepository,
+			Senders:                []domain.NotificationSender{email},
+		})
+
+		err := s.Notify(ctx, &domain.Notification{UserUID: user.UID, Category: domain.NotificationCategoryTask, Title: "Deworm goats"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, s.Wait(ctx))
+		notificationRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		email.AssertExpectations(t)
+	})
+
+	t.Run("returns before slow deliveries, which are bounded by the send timeout", func(t *testing.T) {
+		notificationRepository := new(mocks.MockNotificationRepository)
+		userRepository := new(mocks.MockUserRepository)
+		email := newSender(domain.NotificationChannelEmail)
+
+		var sendCtx context.Context
+		sent := make(chan struct{})
+
+		notificationRepository.On("Create", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil)
+		notificationRepository.On("FindPreference", ctx, user.UID).Return(nil, apperrors.NewNotFound(errors.New("not found")))
+		userRepository.On("FindByID", ctx, user.UID).Return(user, nil)
+		email.On("Send", mock.Anything, user, mock.AnythingOfType("*domain.NotificationPreference"), mock.AnythingOfType("*domain.Notification")).
+			Run(func(args mock.Arguments) {
+				// A provider that never answers, giving up with the context only
+				sendCtx = args.Get(0).(context.Context)
+				<-sendCtx.Done()
+				close(sent)
+			}).
+			Return(context.DeadlineExceeded)
+
+		s := NewNotification(&NotificationServiceConfig{
+			NotificationRepository: notificationRepository,
+			UserRepository:         userRepository,
+			Senders:                []domain.NotificationSender{email},
+			SendTimeout:            50 * time.Millisecond,
+		})
+
+		err := s.Notify(ctx, &domain.Notification{UserUID: user.UID, Category: domain.NotificationCategorySystem, Title: "Welcome"})
+
+		assert.NoError(t, err)
+		select {
+		case <-sent:
+			t.Fatal("Notify waited for the delivery")
+		default:
+		}
+
+		assert.NoError(t, s.Wait(ctx))
+		assert.ErrorIs(t, sendCtx.Err(), context.DeadlineExceeded)
+	})
+}
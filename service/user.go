@@ -16,6 +16,7 @@ import (
 type userService struct {
 	userRepository domain.UserRepository
 	authRepository domain.AuthRepository
+	transactor     domain.Transactor
 }
 
 // UserServiceConfig will hold repositories that will eventually be injected into this
@@ -23,6 +24,7 @@ type userService struct {
 type UserServiceConfig struct {
 	UserRepository domain.UserRepository
 	AuthRepository domain.AuthRepository
+	Transactor     domain.Transactor
 }
 
 // NewUser is a factory function for
@@ -38,6 +40,10 @@ func NewUser(c *UserServiceConfig) domain.UserService {
 		service.authRepository = c.AuthRepository
 	}
 
+	if c.Transactor != nil {
+		service.transactor = c.Transactor
+	}
+
 	return service
 }
 
@@ -108,3 +114,22 @@ func (u *userService) LogOut(ctx context.Context, uid ulid.ULID) error {
 func (u *userService) Get(ctx context.Context, uid ulid.ULID) (*domain.User, error) {
 	return u.userRepository.FindByID(ctx, uid)
 }
+
+// SetAdmin grants or revokes the administrator rights of the user with the given unique identifier.
+// Returns a not found error if the user does not exist, or an internal error if the update fails.
+func (u *userService) SetAdmin(ctx context.Context, uid ulid.ULID, admin bool) error {
+	return u.userRepository.UpdateAdmin(ctx, uid, admin)
+}
+
+// CreateAdmin signs the user up and grants them administrator rights within one transaction,
+// so a failure to grant the rights does not leave a regular account behind.
+// Returns an error if the sign-up or the update fails.
+func (u *userService) CreateAdmin(ctx context.Context, user *domain.User) error {
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.SignUp(ctx, user); err != nil {
+			return err
+		}
+
+		return u.SetAdmin(ctx, user.UID, true)
+	})
+}
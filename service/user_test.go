@@ -1 +1,57 @@
 package service
+
+import (
+	"context"
+	"errors"
+	"github.com/j03hanafi/seternak-backend/domain"
+	"github.com/j03hanafi/seternak-backend/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestUserService_CreateAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("signs up and grants admin rights within a transaction", func(t *testing.T) {
+		userRepository := new(mocks.MockUserRepository)
+		transactor := new(mocks.MockTransactor)
+
+		transactor.On("WithinTransaction", ctx).Return(nil)
+		userRepository.On("Create", ctx, mock.AnythingOfType("*domain.User")).Return(nil)
+		userRepository.On("UpdateAdmin", ctx, mock.Anything, true).Return(nil)
+
+		s := NewUser(&UserServiceConfig{
+			UserRepository: userRepository,
+			Transactor:     transactor,
+		})
+
+		u := &domain.User{Email: "admin@seternak.id", Password: "s3cretpass", Name: "Admin"}
+		err := s.CreateAdmin(ctx, u)
+
+		assert.NoError(t, err)
+		assert.NotZero(t, u.UID)
+		transactor.AssertExpectations(t)
+		userRepository.AssertCalled(t, "UpdateAdmin", ctx, u.UID, true)
+	})
+
+	t.Run("returns the error that rolls the transaction back", func(t *testing.T) {
+		userRepository := new(mocks.MockUserRepository)
+		transactor := new(mocks.MockTransactor)
+		errUpdate := errors.New("update failed")
+
+		transactor.On("WithinTransaction", ctx).Return(nil)
+		userRepository.On("Create", ctx, mock.AnythingOfType("*domain.User")).Return(nil)
+		userRepository.On("UpdateAdmin", ctx, mock.Anything, true).Return(errUpdate)
+
+		s := NewUser(&UserServiceConfig{
+			UserRepository: userRepository,
+			Transactor:     transactor,
+		})
+
+		err := s.CreateAdmin(ctx, &domain.User{Email: "admin@seternak.id", Password: "s3cretpass", Name: "Admin"})
+
+		assert.ErrorIs(t, err, errUpdate)
+		transactor.AssertExpectations(t)
+	})
+}
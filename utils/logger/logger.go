@@ -4,12 +4,30 @@ import (
 	"context"
 	"github.com/j03hanafi/seternak-backend/utils/consts"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 	"os"
 	"runtime/debug"
 	"sync"
+	"time"
+)
+
+// Set of valid LOG_FORMAT values
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
+// Set of LOG_FILE values with a special meaning
+const (
+	// FileDefault writes to productionFile in production and to no file otherwise
+	FileDefault = ""
+	// FileNone turns the file sink off, in production too
+	FileNone = "none"
+
+	productionFile = "logs/app.log"
 )
 
 type ctxKey struct{}
@@ -17,43 +35,32 @@ type ctxKey struct{}
 var (
 	once   sync.Once
 	logger *zap.Logger
+	level  zap.AtomicLevel
 )
 
 // Get initializes a zap.Logger instance if it has not been initialized
 // already and returns the same instance for subsequent calls.
+// It is configured by the LOG_* settings, so config.Load must run first.
 func Get() *zap.Logger {
 	once.Do(func() {
-		// Console Log
-		stdout := zapcore.AddSync(os.Stdout)
+		production := viper.GetString("APP_ENV") == consts.ProductionMode
 
-		developmentCfg := zap.NewDevelopmentEncoderConfig()
-		developmentCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
-
-		consoleEncoder := zapcore.NewConsoleEncoder(developmentCfg)
-
-		// File Log
-		filename := "logs/app.log"
-		if viper.GetString("APP_ENV") == consts.TestMode {
-			filename = "../logs/app.log"
+		// Default to debug outside production, unless LOG_LEVEL says otherwise
+		defaultLevel := zap.DebugLevel
+		if production {
+			defaultLevel = zap.InfoLevel
 		}
-		file := zapcore.AddSync(&lumberjack.Logger{
-			Filename:   filename,
-			MaxSize:    5,
-			MaxAge:     30,
-			MaxBackups: 15,
-			LocalTime:  true,
-			Compress:   true,
-		})
-
-		productionCfg := zap.NewProductionEncoderConfig()
-		productionCfg.TimeKey = "timestamp"
-		productionCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+		level = zap.NewAtomicLevelAt(defaultLevel)
 
-		fileEncoder := zapcore.NewJSONEncoder(productionCfg)
+		var levelErr error
+		if l := viper.GetString("LOG_LEVEL"); l != "" {
+			levelErr = level.UnmarshalText([]byte(l))
+		}
 
-		var gitRevision string
+		var gitRevision, goVersion string
 		buildInfo, ok := debug.ReadBuildInfo()
 		if ok {
+			goVersion = buildInfo.GoVersion
 			for _, v := range buildInfo.Settings {
 				if v.Key == "vcs.revision" {
 					gitRevision = v.Value
@@ -62,35 +69,89 @@ func Get() *zap.Logger {
 			}
 		}
 
-		// log to multiple destinations (console and file)
 		// extra fields are added to the JSON output alone
-		var (
-			level = zap.NewAtomicLevelAt(zap.InfoLevel)
-			core  zapcore.Core
-			opts  []zap.Option
-		)
+		buildFields := []zapcore.Field{
+			zap.String("gitRevision", gitRevision),
+			zap.String("goVersion", goVersion),
+		}
 
-		if viper.GetString("APP_ENV") != consts.ProductionMode {
-			level = zap.NewAtomicLevelAt(zap.DebugLevel)
-			opts = append(opts, zap.Development(), zap.AddCaller(), zap.AddStacktrace(zap.DPanicLevel))
-			core = zapcore.NewCore(consoleEncoder, stdout, level)
+		productionCfg := zap.NewProductionEncoderConfig()
+		productionCfg.TimeKey = "timestamp"
+		productionCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+
+		// Console Log, JSON for container deployments collecting stdout
+		stdout := zapcore.AddSync(os.Stdout)
+
+		var cores []zapcore.Core
+		if viper.GetString("LOG_FORMAT") == FormatJSON {
+			cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(productionCfg), stdout, level).With(buildFields))
 		} else {
-			core = zapcore.NewTee(
-				zapcore.NewCore(consoleEncoder, stdout, level),
-				zapcore.NewCore(fileEncoder, file, level).
-					With([]zapcore.Field{
-						zap.String("gitRevision", gitRevision),
-						zap.String("goVersion", buildInfo.GoVersion),
-					}),
-			)
+			developmentCfg := zap.NewDevelopmentEncoderConfig()
+			developmentCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+
+			cores = append(cores, zapcore.NewCore(zapcore.NewConsoleEncoder(developmentCfg), stdout, level))
+		}
+
+		// File Log, rotated by lumberjack
+		filename := viper.GetString("LOG_FILE")
+		if filename == FileDefault && production {
+			filename = productionFile
+		}
+
+		if filename != FileDefault && filename != FileNone {
+			file := zapcore.AddSync(&lumberjack.Logger{
+				Filename:   filename,
+				MaxSize:    viper.GetInt("LOG_FILE_MAX_SIZE"),
+				MaxAge:     viper.GetInt("LOG_FILE_MAX_AGE"),
+				MaxBackups: viper.GetInt("LOG_FILE_MAX_BACKUPS"),
+				LocalTime:  true,
+				Compress:   viper.GetBool("LOG_FILE_COMPRESS"),
+			})
+
+			cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(productionCfg), file, level).With(buildFields))
+		}
+
+		// log to multiple destinations (console and file)
+		core := wrapCore(zapcore.NewTee(cores...))
+
+		var opts []zap.Option
+		if !production {
+			opts = append(opts, zap.Development(), zap.AddCaller(), zap.AddStacktrace(zap.DPanicLevel))
 		}
 
 		logger = zap.New(core, opts...)
+
+		if levelErr != nil {
+			logger.Warn("Invalid LOG_LEVEL, using the default level",
+				zap.Error(levelErr),
+				zap.Stringer("defaultLevel", level),
+			)
+		}
 	})
 
 	return logger
 }
 
+// wrapCore masks sensitive fields before they reach core and, if LOG_SAMPLING is on, drops
+// repeated entries past the first ones every second to bound logging cost under load.
+func wrapCore(core zapcore.Core) zapcore.Core {
+	core = &redactCore{Core: core}
+
+	// The sampler must come first, as redactCore.Check does not call the Check of the core it wraps
+	if viper.GetBool("LOG_SAMPLING") {
+		core = zapcore.NewSamplerWithOptions(core, time.Second,
+			viper.GetInt("LOG_SAMPLING_INITIAL"), viper.GetInt("LOG_SAMPLING_THEREAFTER"))
+	}
+
+	return core
+}
+
+// Level returns the level of the logger returned by Get, which can be changed at runtime.
+func Level() zap.AtomicLevel {
+	Get()
+	return level
+}
+
 // FromCtx returns the Logger associated with the ctx. If no logger
 // is associated, the default logger is returned, unless it is nil
 // in which case a disabled logger is returned.
@@ -105,12 +166,21 @@ func FromCtx(ctx context.Context) *zap.Logger {
 }
 
 // WithCtx returns a copy of ctx with the Logger attached.
+// If ctx carries a span and no Logger yet, the trace and span IDs are added to every
+// entry of the Logger. Loggers replacing an attached one are expected to derive from it
+// through FromCtx, so they carry those IDs already.
 func WithCtx(ctx context.Context, l *zap.Logger) context.Context {
-	if lp, ok := ctx.Value(ctxKey{}).(*zap.Logger); ok {
-		if lp == l {
-			// Do not store same logger.
-			return ctx
-		}
+	lp, ok := ctx.Value(ctxKey{}).(*zap.Logger)
+	if ok && lp == l {
+		// Do not store same logger.
+		return ctx
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); !ok && sc.IsValid() {
+		l = l.With(
+			zap.String("traceId", sc.TraceID().String()),
+			zap.String("spanId", sc.SpanID().String()),
+		)
 	}
 
 	return context.WithValue(ctx, ctxKey{}, l)
@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+func TestWrapCore(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	t.Run("Sampling drops repeated entries", func(t *testing.T) {
+		viper.Set("LOG_SAMPLING", true)
+		viper.Set("LOG_SAMPLING_INITIAL", 10)
+		viper.Set("LOG_SAMPLING_THEREAFTER", 20)
+
+		core, logs := observer.New(zap.DebugLevel)
+		l := zap.New(wrapCore(core))
+
+		for i := 0; i < 50; i++ {
+			l.Info("repeated", zap.String("password", "hunter2"))
+		}
+
+		// The first 10, then every 20th: the 30th and the 50th
+		assert.Equal(t, 12, logs.Len())
+		assert.Equal(t, redacted, logs.All()[0].ContextMap()["password"])
+	})
+
+	t.Run("Every entry is kept without sampling", func(t *testing.T) {
+		viper.Set("LOG_SAMPLING", false)
+
+		core, logs := observer.New(zap.DebugLevel)
+		l := zap.New(wrapCore(core))
+
+		for i := 0; i < 50; i++ {
+			l.Info("repeated")
+		}
+
+		assert.Equal(t, 50, logs.Len())
+	})
+}
@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the value of sensitive fields
+const redacted = "[REDACTED]"
+
+var (
+	// sensitiveKeyParts are the key fragments marking a value as sensitive wherever they appear
+	sensitiveKeyParts = []string{"password", "passwd", "secret", "authorization", "cookie"}
+
+	// keyNormalizer lets request_token, request-token and requestToken match alike
+	keyNormalizer = strings.NewReplacer("_", "", "-", "")
+
+	// jsonPair matches a "key": "value" pair in JSON bodies
+	jsonPair = regexp.MustCompile(`"([\w-]+)"\s*:\s*"(?:[^"\\]|\\.)*"`)
+
+	// queryPair matches a key=value pair in query strings
+	queryPair = regexp.MustCompile(`([\w-]+)=([^&\s]*)`)
+)
+
+// isSensitive reports whether a field, JSON or query key holds a secret. Keys naming a
+// token itself, like id_token or refreshToken, are sensitive; token IDs are not.
+func isSensitive(key string) bool {
+	key = strings.ToLower(keyNormalizer.Replace(key))
+
+	if strings.HasSuffix(key, "token") {
+		return true
+	}
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactString masks the values of sensitive keys found in JSON or query strings within s
+func redactString(s string) string {
+	if strings.Contains(s, `"`) {
+		s = jsonPair.ReplaceAllStringFunc(s, func(pair string) string {
+			key := jsonPair.FindStringSubmatch(pair)[1]
+			if !isSensitive(key) {
+				return pair
+			}
+			return `"` + key + `":"` + redacted + `"`
+		})
+	}
+
+	if strings.Contains(s, "=") {
+		s = queryPair.ReplaceAllStringFunc(s, func(pair string) string {
+			key := queryPair.FindStringSubmatch(pair)[1]
+			if !isSensitive(key) {
+				return pair
+			}
+			return key + "=" + redacted
+		})
+	}
+
+	return s
+}
+
+// redact returns fields with the values of sensitive fields masked,
+// copying fields only when one of them has to change
+func redact(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+
+	for i, f := range fields {
+		r, changed := f, true
+		switch {
+		case isSensitive(f.Key):
+			r = zap.String(f.Key, redacted)
+		case f.Type == zapcore.StringType:
+			r.String = redactString(f.String)
+			changed = r.String != f.String
+		case f.Type == zapcore.ByteStringType:
+			b, _ := f.Interface.([]byte)
+			value := redactString(string(b))
+			r, changed = zap.String(f.Key, value), value != string(b)
+		default:
+			changed = false
+		}
+
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, r)
+	}
+
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// redactCore is a zapcore.Core masking sensitive fields before they reach the wrapped core,
+// so secrets never end up in any sink whatever the call site logs.
+type redactCore struct {
+	zapcore.Core
+}
+
+// With adds redacted fields to the core
+func (r *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: r.Core.With(redact(fields))}
+}
+
+// Check adds the core to ce when the entry is enabled, so that Write goes through redaction
+func (r *redactCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if r.Enabled(e.Level) {
+		return ce.AddCore(e, r)
+	}
+	return ce
+}
+
+// Write writes the entry with its fields redacted
+func (r *redactCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	return r.Core.Write(e, redact(fields))
+}
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+func TestRedactCore(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	l := zap.New(&redactCore{Core: core}).With(zap.String("password", "hunter2"))
+
+	l.Info("request",
+		zap.String("tokenID", "01HG0000000000000000000000"),
+		zap.String("refresh_token", "eyJhbGciOi"),
+		zap.ByteString("resBody", []byte(`{"id_token":"eyJhbGciOi","name":"Budi"}`)),
+		zap.String("queryParams", "page=2&access_token=eyJhbGciOi"),
+		zap.Int("status", 200),
+	)
+
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, redacted, fields["password"])
+	assert.Equal(t, "01HG0000000000000000000000", fields["tokenID"])
+	assert.Equal(t, redacted, fields["refresh_token"])
+	assert.Equal(t, `{"id_token":"[REDACTED]","name":"Budi"}`, fields["resBody"])
+	assert.Equal(t, "page=2&access_token=[REDACTED]", fields["queryParams"])
+	assert.EqualValues(t, 200, fields["status"])
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+	"time"
+)
+
+// gormStartKey is the gorm instance key holding the start time of a statement
+const gormStartKey = "metrics:start"
+
+var dbQueries = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "Database statement latency by operation, table and result.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"operation", "table", "result"})
+
+// RegisterDB exposes the connection pool stats of db, backed by pgx,
+// and records the latency of every statement gorm runs.
+func RegisterDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	if err = register(collectors.NewDBStatsCollector(sqlDB, "postgres")); err != nil {
+		return err
+	}
+
+	return db.Use(&gormPlugin{})
+}
+
+// gormPlugin is a gorm.Plugin timing statements through callbacks
+type gormPlugin struct{}
+
+// Name satisfies gorm.Plugin
+func (p *gormPlugin) Name() string {
+	return "metrics"
+}
+
+// Initialize satisfies gorm.Plugin by registering callbacks around every operation
+func (p *gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	return errors.Join(
+		cb.Create().Before("*").Register("metrics:before_create", before),
+		cb.Create().After("*").Register("metrics:after_create", after("create")),
+		cb.Query().Before("*").Register("metrics:before_query", before),
+		cb.Query().After("*").Register("metrics:after_query", after("query")),
+		cb.Update().Before("*").Register("metrics:before_update", before),
+		cb.Update().After("*").Register("metrics:after_update", after("update")),
+		cb.Delete().Before("*").Register("metrics:before_delete", before),
+		cb.Delete().After("*").Register("metrics:after_delete", after("delete")),
+		cb.Row().Before("*").Register("metrics:before_row", before),
+		cb.Row().After("*").Register("metrics:after_row", after("row")),
+		cb.Raw().Before("*").Register("metrics:before_raw", before),
+		cb.Raw().After("*").Register("metrics:after_raw", after("raw")),
+	)
+}
+
+// before stores the start time of the statement
+func before(db *gorm.DB) {
+	db.InstanceSet(gormStartKey, time.Now())
+}
+
+// after observes the statement latency
+func after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(gormStartKey)
+		if !ok {
+			return
+		}
+		start, _ := value.(time.Time)
+
+		result := "success"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			result = "failure"
+		}
+
+		dbQueries.WithLabelValues(operation, db.Statement.Table, result).Observe(time.Since(start).Seconds())
+	}
+}
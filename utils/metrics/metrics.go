@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"strconv"
+	"time"
+)
+
+const namespace = "seternak"
+
+// Set of authentication events counted by AuthEvent
+const (
+	AuthSignUp       = "signup"
+	AuthLogIn        = "login"
+	AuthLogOut       = "logout"
+	AuthTokenRefresh = "token_refresh"
+)
+
+// UnmatchedRoute labels requests that did not match any registered route,
+// so arbitrary request paths never become label values
+const UnmatchedRoute = "unmatched"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests being served.",
+	})
+
+	authEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "events_total",
+		Help:      "Number of authentication events by event and result.",
+	}, []string{"event", "result"})
+)
+
+// HTTPRequestStarted tracks a request being served until the returned func is called
+// with the matched route template and the response status code.
+func HTTPRequestStarted(method string) func(route string, status int) {
+	start := time.Now()
+	httpInFlight.Inc()
+
+	return func(route string, status int) {
+		httpInFlight.Dec()
+
+		code := strconv.Itoa(status)
+		httpRequests.WithLabelValues(method, route, code).Inc()
+		httpDuration.WithLabelValues(method, route, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// AuthEvent counts an authentication event, as a failure when err is not nil.
+func AuthEvent(event string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	authEvents.WithLabelValues(event, result).Inc()
+}
+
+// register registers c with the default registry, ignoring collectors that are already registered
+// so the server can be initialized more than once in the same process, e.g. in tests.
+func register(c prometheus.Collector) error {
+	err := prometheus.Register(c)
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		return nil
+	}
+
+	return err
+}
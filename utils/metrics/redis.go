@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// RegisterRedis exposes the connection pool stats of the Redis client.
+func RegisterRedis(client *redis.Client) error {
+	return register(&redisCollector{client: client})
+}
+
+var (
+	redisHits = prometheus.NewDesc(namespace+"_redis_pool_hits_total",
+		"Number of times a free connection was found in the pool.", nil, nil)
+	redisMisses = prometheus.NewDesc(namespace+"_redis_pool_misses_total",
+		"Number of times a free connection was not found in the pool.", nil, nil)
+	redisTimeouts = prometheus.NewDesc(namespace+"_redis_pool_timeouts_total",
+		"Number of times a wait for a connection timed out.", nil, nil)
+	redisTotalConns = prometheus.NewDesc(namespace+"_redis_pool_connections",
+		"Number of connections in the pool.", nil, nil)
+	redisIdleConns = prometheus.NewDesc(namespace+"_redis_pool_idle_connections",
+		"Number of idle connections in the pool.", nil, nil)
+	redisStaleConns = prometheus.NewDesc(namespace+"_redis_pool_stale_connections_total",
+		"Number of stale connections removed from the pool.", nil, nil)
+)
+
+// redisCollector is a prometheus.Collector reading go-redis pool stats on every scrape
+type redisCollector struct {
+	client *redis.Client
+}
+
+// Describe satisfies prometheus.Collector
+func (c *redisCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisHits
+	ch <- redisMisses
+	ch <- redisTimeouts
+	ch <- redisTotalConns
+	ch <- redisIdleConns
+	ch <- redisStaleConns
+}
+
+// Collect satisfies prometheus.Collector
+func (c *redisCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+
+	ch <- prometheus.MustNewConstMetric(redisHits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(redisMisses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(redisTimeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisTotalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisIdleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(redisStaleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
@@ -0,0 +1,236 @@
+package replica
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/j03hanafi/seternak-backend/utils/logger"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Replica is a read replica of the primary database
+type Replica struct {
+	// Name identifies the replica in logs, without exposing its credentials
+	Name string
+	DB   *sql.DB
+
+	healthy atomic.Bool
+}
+
+// Router works along with dbresolver to route reads to healthy read replicas.
+// It acts as the dbresolver.Policy picking a replica, and as a gorm.Plugin sending
+// reads to the primary instead when every replica is down or, with read-your-writes,
+// when the request has written to the primary already.
+type Router struct {
+	replicas       []*Replica
+	readYourWrites bool
+	checkInterval  time.Duration
+	checkTimeout   time.Duration
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// RouterConfig will hold the replicas and settings of the Router
+type RouterConfig struct {
+	Replicas       []*Replica
+	ReadYourWrites bool
+	CheckInterval  time.Duration
+	CheckTimeout   time.Duration
+}
+
+// NewRouter is a factory function for initializing a Router.
+// Every replica is assumed healthy until checked.
+func NewRouter(c *RouterConfig) *Router {
+	for _, r := range c.Replicas {
+		r.healthy.Store(true)
+	}
+
+	return &Router{
+		replicas:       c.Replicas,
+		readYourWrites: c.ReadYourWrites,
+		checkInterval:  c.CheckInterval,
+		checkTimeout:   c.CheckTimeout,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Dialectors returns the dialectors of the replicas, to be registered as dbresolver replicas
+func (r *Router) Dialectors() []gorm.Dialector {
+	dialectors := make([]gorm.Dialector, 0, len(r.replicas))
+	for _, replica := range r.replicas {
+		dialectors = append(dialectors, postgres.New(postgres.Config{Conn: replica.DB}))
+	}
+	return dialectors
+}
+
+// Resolve satisfies dbresolver.Policy by picking a random healthy replica,
+// or a random replica if none is healthy.
+func (r *Router) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	healthy := make([]gorm.ConnPool, 0, len(pools))
+	for _, pool := range pools {
+		if r.isHealthy(pool) {
+			healthy = append(healthy, pool)
+		}
+	}
+
+	if len(healthy) == 0 {
+		healthy = pools
+	}
+
+	return healthy[rand.Intn(len(healthy))]
+}
+
+// isHealthy reports whether pool belongs to a healthy replica
+func (r *Router) isHealthy(pool gorm.ConnPool) bool {
+	for _, replica := range r.replicas {
+		if gorm.ConnPool(replica.DB) == pool {
+			return replica.healthy.Load()
+		}
+	}
+	return false
+}
+
+// anyHealthy reports whether at least one replica is healthy
+func (r *Router) anyHealthy() bool {
+	for _, replica := range r.replicas {
+		if replica.healthy.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+// Name satisfies gorm.Plugin
+func (r *Router) Name() string {
+	return "replica"
+}
+
+// Initialize satisfies gorm.Plugin by registering callbacks to route reads
+// and to track writes. It must be used after dbresolver.
+func (r *Router) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	return errors.Join(
+		cb.Query().Before("gorm:query").Register("replica:route", r.route),
+		cb.Row().Before("gorm:row").Register("replica:route", r.route),
+		cb.Raw().Before("gorm:raw").Register("replica:route", r.route),
+		cb.Create().After("gorm:create").Register("replica:track_write", r.trackWrite),
+		cb.Update().After("gorm:update").Register("replica:track_write", r.trackWrite),
+		cb.Delete().After("gorm:delete").Register("replica:track_write", r.trackWrite),
+		cb.Raw().After("gorm:raw").Register("replica:track_write", r.trackWrite),
+	)
+}
+
+// route sends the statement to the primary when no replica can serve it consistently
+func (r *Router) route(db *gorm.DB) {
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+		// Transactions stay on the connection they started on
+		return
+	}
+
+	if !r.anyHealthy() || (r.readYourWrites && hasWritten(db.Statement.Context)) {
+		dbresolver.Write.ModifyStatement(db.Statement)
+	}
+}
+
+// trackWrite marks the request session as having written to the primary
+func (r *Router) trackWrite(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Context == nil {
+		return
+	}
+
+	if db.Statement.SQL.Len() > 0 && strings.HasPrefix(strings.ToLower(strings.TrimSpace(db.Statement.SQL.String())), "select") {
+		return
+	}
+
+	if s, ok := db.Statement.Context.Value(sessionKey{}).(*session); ok {
+		s.written.Store(true)
+	}
+}
+
+// Start checks the health of every replica, then keeps checking it in the background until Close.
+func (r *Router) Start() {
+	r.check()
+
+	r.done.Add(1)
+	go func() {
+		defer r.done.Done()
+
+		ticker := time.NewTicker(r.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.check()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// check pings every replica and records whether it answered in time
+func (r *Router) check() {
+	l := logger.Get()
+
+	for _, replica := range r.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), r.checkTimeout)
+		err := replica.DB.PingContext(ctx)
+		cancel()
+
+		healthy := err == nil
+		if replica.healthy.Swap(healthy) == healthy {
+			continue
+		}
+
+		if healthy {
+			l.Info("Read replica is back, routing reads to it", zap.String("replica", replica.Name))
+		} else {
+			l.Warn("Read replica is down, routing reads elsewhere", zap.String("replica", replica.Name), zap.Error(err))
+		}
+	}
+}
+
+// Close stops the health checks and closes the connections to the replicas
+func (r *Router) Close() error {
+	close(r.stop)
+	r.done.Wait()
+
+	var errs []error
+	for _, replica := range r.replicas {
+		errs = append(errs, replica.DB.Close())
+	}
+	return errors.Join(errs...)
+}
+
+type sessionKey struct{}
+
+// session tracks whether a request has written to the primary
+type session struct {
+	written atomic.Bool
+}
+
+// WithSession returns a copy of ctx starting a read-your-writes session, in which reads
+// following a write through ctx go to the primary rather than to a lagging replica.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, new(session))
+}
+
+// hasWritten reports whether the session in ctx, if any, has written to the primary
+func hasWritten(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	s, ok := ctx.Value(sessionKey{}).(*session)
+	return ok && s.written.Load()
+}
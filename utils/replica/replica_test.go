@@ -0,0 +1,137 @@
+package replica
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+	"testing"
+	"time"
+)
+
+type animal struct {
+	ID   uint
+	Name string
+}
+
+// openPool opens a connection pool that never connects unless used
+func openPool(t *testing.T, host string) *sql.DB {
+	db, err := sql.Open("pgx", "host="+host+" port=1 connect_timeout=1")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// setup returns a dry run DB with one primary and two replicas routed by a Router
+func setup(t *testing.T, readYourWrites bool) (*gorm.DB, *sql.DB, *Router) {
+	primary := openPool(t, "primary")
+	router := NewRouter(&RouterConfig{
+		Replicas: []*Replica{
+			{Name: "replica-1", DB: openPool(t, "replica-1")},
+			{Name: "replica-2", DB: openPool(t, "replica-2")},
+		},
+		ReadYourWrites: readYourWrites,
+		CheckInterval:  time.Minute,
+		CheckTimeout:   time.Second,
+	})
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: primary}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: router.Dialectors(),
+		Policy:   router,
+	})))
+	require.NoError(t, db.Use(router))
+
+	return db, primary, router
+}
+
+// readPool returns the connection pool a read through ctx is routed to
+func readPool(db *gorm.DB, ctx context.Context) gorm.ConnPool {
+	return db.WithContext(ctx).Find(&[]animal{}).Statement.ConnPool
+}
+
+func TestRouter_Routing(t *testing.T) {
+	t.Run("Reads go to a replica and writes to the primary", func(t *testing.T) {
+		db, primary, router := setup(t, true)
+		ctx := context.Background()
+
+		for i := 0; i < 10; i++ {
+			pool := readPool(db, ctx)
+			assert.NotEqual(t, gorm.ConnPool(primary), pool)
+			assert.True(t, router.isHealthy(pool))
+		}
+
+		pool := db.WithContext(ctx).Create(&animal{Name: "Bessie"}).Statement.ConnPool
+		assert.Equal(t, gorm.ConnPool(primary), pool)
+	})
+
+	t.Run("Reads after a write in a session go to the primary", func(t *testing.T) {
+		db, primary, _ := setup(t, true)
+		ctx := WithSession(context.Background())
+
+		assert.NotEqual(t, gorm.ConnPool(primary), readPool(db, ctx))
+
+		db.WithContext(ctx).Create(&animal{Name: "Bessie"})
+
+		assert.Equal(t, gorm.ConnPool(primary), readPool(db, ctx))
+		// Other sessions still read from replicas
+		assert.NotEqual(t, gorm.ConnPool(primary), readPool(db, WithSession(context.Background())))
+	})
+
+	t.Run("Reads after a write go to a replica without read-your-writes", func(t *testing.T) {
+		db, primary, _ := setup(t, false)
+		ctx := WithSession(context.Background())
+
+		db.WithContext(ctx).Create(&animal{Name: "Bessie"})
+
+		assert.NotEqual(t, gorm.ConnPool(primary), readPool(db, ctx))
+	})
+
+	t.Run("Raw selects do not count as writes", func(t *testing.T) {
+		db, primary, _ := setup(t, true)
+		ctx := WithSession(context.Background())
+
+		db.WithContext(ctx).Exec("SELECT 1")
+
+		assert.NotEqual(t, gorm.ConnPool(primary), readPool(db, ctx))
+	})
+
+	t.Run("Reads skip unhealthy replicas", func(t *testing.T) {
+		db, _, router := setup(t, true)
+		router.replicas[0].healthy.Store(false)
+
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, gorm.ConnPool(router.replicas[1].DB), readPool(db, context.Background()))
+		}
+	})
+
+	t.Run("Reads fall back to the primary when every replica is down", func(t *testing.T) {
+		db, primary, router := setup(t, true)
+		for _, r := range router.replicas {
+			r.healthy.Store(false)
+		}
+
+		assert.Equal(t, gorm.ConnPool(primary), readPool(db, context.Background()))
+	})
+}
+
+func TestRouter_Check(t *testing.T) {
+	// Nothing listens on port 1, so pings fail right away
+	_, _, router := setup(t, true)
+
+	router.check()
+
+	for _, r := range router.replicas {
+		assert.False(t, r.healthy.Load(), r.Name)
+	}
+	assert.False(t, router.anyHealthy())
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey is the gorm instance key holding the span of a statement
+const gormSpanKey = "tracing:span"
+
+// RegisterDB creates a span for every statement gorm runs, as a child of the
+// span in the statement context set by WithContext.
+func RegisterDB(db *gorm.DB) error {
+	return db.Use(&gormPlugin{})
+}
+
+// gormPlugin is a gorm.Plugin creating spans through callbacks
+type gormPlugin struct{}
+
+// Name satisfies gorm.Plugin
+func (p *gormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize satisfies gorm.Plugin by registering callbacks around every operation
+func (p *gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	return errors.Join(
+		cb.Create().Before("*").Register("tracing:before_create", before("create")),
+		cb.Create().After("*").Register("tracing:after_create", after),
+		cb.Query().Before("*").Register("tracing:before_query", before("query")),
+		cb.Query().After("*").Register("tracing:after_query", after),
+		cb.Update().Before("*").Register("tracing:before_update", before("update")),
+		cb.Update().After("*").Register("tracing:after_update", after),
+		cb.Delete().Before("*").Register("tracing:before_delete", before("delete")),
+		cb.Delete().After("*").Register("tracing:after_delete", after),
+		cb.Row().Before("*").Register("tracing:before_row", before("row")),
+		cb.Row().After("*").Register("tracing:after_row", after),
+		cb.Raw().Before("*").Register("tracing:before_raw", before("raw")),
+		cb.Raw().After("*").Register("tracing:after_raw", after),
+	)
+}
+
+// before starts the span of the statement
+func before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			return
+		}
+
+		_, span := Start(ctx, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperation(operation)),
+		)
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+// after ends the span of the statement. Only the SQL with placeholders is recorded,
+// never the bound values, as those may hold passwords or tokens.
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, _ := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBSQLTable(db.Statement.Table),
+		semconv.DBStatement(db.Statement.SQL.String()),
+	)
+
+	if !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		RecordError(span, db.Error)
+	}
+}